@@ -31,9 +31,9 @@ func commands(ctx context.Context) []*cli.Command {
 		{
 			Name:    "remove-followers",
 			Aliases: []string{"rm", "remove"},
-			Usage:   "Remove a list of followers, by username.",
+			Usage:   "Remove a list of followers, by username or from the file.",
 			Action:  executeCmd(ctx, cmdRemoveFollowers),
-			Flags:   []cli.Flag{addUsersFlag()},
+			Flags:   removeFollowersFlags(),
 		},
 		{
 			Name:    "unfollow-users",
@@ -49,6 +49,43 @@ func commands(ctx context.Context) []*cli.Command {
 			Action:  executeCmd(ctx, cmdFollowUsers),
 			Flags:   []cli.Flag{addUsersFlag()},
 		},
+		{
+			Name:    "block-users",
+			Aliases: []string{"block"},
+			Usage:   "Block a list of users, by username.",
+			Action:  executeCmd(ctx, cmdBlockUsers),
+			Flags:   []cli.Flag{addUsersFlag()},
+		},
+		{
+			Name:    "unblock-users",
+			Aliases: []string{"unblock"},
+			Usage:   "Unblock a list of blocked users, by username.",
+			Action:  executeCmd(ctx, cmdUnblockUsers),
+			Flags:   []cli.Flag{addUsersFlag()},
+		},
+		{
+			Name:  "blocked",
+			Usage: "Blocked accounts",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List blocked accounts",
+					Action: executeCmd(ctx, cmdListBlocked),
+					Flags:  []cli.Flag{addListFlag()},
+				},
+				{
+					Name:   "diff",
+					Usage:  "List newly blocked and unblocked accounts since previous list",
+					Action: executeCmd(ctx, cmdListDiffBlocked),
+					Flags:  []cli.Flag{addListFlag()},
+				},
+				{
+					Name:   "history",
+					Usage:  "List history of blocked accounts changes",
+					Action: executeCmd(ctx, cmdListHistoryDiffBlocked),
+				},
+			},
+		},
 		{
 			Name:    "list-unmutual",
 			Aliases: []string{"unmutual"},
@@ -63,6 +100,38 @@ func commands(ctx context.Context) []*cli.Command {
 			Action:  executeCmd(ctx, cmdListUseless),
 			Flags:   []cli.Flag{addListFlag()},
 		},
+		{
+			Name:    "ghost-followers",
+			Aliases: []string{"ghosts"},
+			Usage:   "List followers that neither liked nor commented the last posts",
+			Action:  executeCmd(ctx, cmdGhostFollowers),
+			Flags:   ghostFollowersFlags(),
+		},
+		{
+			Name:    "engagement",
+			Aliases: []string{"stats"},
+			Usage:   "Show posts engagement rate over time, top posts and the most engaged followers",
+			Action:  executeCmd(ctx, cmdEngagement),
+			Flags:   engagementFlags(),
+		},
+		{
+			Name:  "stories",
+			Usage: "Track story viewers",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "track",
+					Usage:  "Store viewers of active stories before they expire",
+					Action: executeCmd(ctx, cmdTrackStoryViewers),
+					Flags:  trackStoriesFlags(),
+				},
+				{
+					Name:   "report",
+					Usage:  "Rank followers by viewed stories and list followers that never view",
+					Action: executeCmd(ctx, cmdStoryViewsReport),
+					Flags:  []cli.Flag{addListFlag()},
+				},
+			},
+		},
 		{
 			Name:    "list-diff",
 			Aliases: []string{"diff"},
@@ -83,5 +152,198 @@ func commands(ctx context.Context) []*cli.Command {
 			Action:  executeCmd(ctx, cmdUploadMedia),
 			Flags:   uploadMediaFlags(),
 		},
+		{
+			Name:   "render",
+			Usage:  "Process photos the same way upload does and write results to disk, without login",
+			Action: executeOfflineCmd(cmdRenderMedia),
+			Flags:  renderFlags(),
+		},
+		{
+			Name:      "upload-dir",
+			Usage:     "Upload every photo of the directory, already uploaded ones are skipped",
+			ArgsUsage: "<path>",
+			Action:    executeCmd(ctx, cmdUploadDir),
+			Flags:     uploadDirFlags(),
+		},
+		{
+			Name:  "media",
+			Usage: "Manage posted media",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the last feed posts and active stories with ids",
+					Action: executeCmd(ctx, cmdMediaList),
+					Flags:  mediaListFlags(),
+				},
+				{
+					Name:      "delete",
+					Usage:     "Delete feed post or story, by id",
+					ArgsUsage: "<id>",
+					Action:    executeCmd(ctx, cmdMediaDelete),
+					Flags:     mediaDeleteFlags(),
+				},
+				{
+					Name:      "archive",
+					Usage:     "Archive feed post, by id",
+					ArgsUsage: "<id>",
+					Action:    executeCmd(ctx, cmdMediaArchive),
+				},
+				{
+					Name:      "edit-caption",
+					Usage:     "Replace feed post caption, by id",
+					ArgsUsage: "<id>",
+					Action:    executeCmd(ctx, cmdMediaEditCaption),
+					Flags:     editCaptionFlags(),
+				},
+				{
+					Name:   "history",
+					Usage:  "List uploaded files with ids of created media",
+					Action: executeCmd(ctx, cmdMediaHistory),
+				},
+			},
+		},
+		{
+			Name:  "queue",
+			Usage: "Schedule posts and upload them when due",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "add",
+					Usage:  "Schedule post upload",
+					Action: executeOfflineCmd(withStorage(cmdQueueAdd)),
+					Flags:  queueAddFlags(),
+				},
+				{
+					Name:   "list",
+					Usage:  "List scheduled posts with their status",
+					Action: executeOfflineCmd(withStorage(cmdQueueList)),
+				},
+				{
+					Name:   "remove",
+					Usage:  "Remove scheduled post, by id",
+					Action: executeOfflineCmd(withStorage(cmdQueueRemove)),
+					Flags:  []cli.Flag{queueIDFlag()},
+				},
+				{
+					Name:   "run",
+					Usage:  "Upload due posts, retrying failed ones with backoff",
+					Action: executeCmd(ctx, cmdQueueRun),
+					Flags:  queueRunFlags(),
+				},
+			},
+		},
+		{
+			Name:    "requests",
+			Aliases: []string{"follow-requests"},
+			Usage:   "Manage pending follow requests (private account)",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List pending follow requests",
+					Action: executeCmd(ctx, cmdListRequests),
+					Flags:  []cli.Flag{addListFlag()},
+				},
+				{
+					Name:   "approve",
+					Usage:  "Approve follow requests, by username",
+					Action: executeCmd(ctx, cmdApproveRequests),
+					Flags:  []cli.Flag{addUsersFlag()},
+				},
+				{
+					Name:   "decline",
+					Usage:  "Decline follow requests, by username",
+					Action: executeCmd(ctx, cmdDeclineRequests),
+					Flags:  []cli.Flag{addUsersFlag()},
+				},
+				{
+					Name:   "approve-all",
+					Usage:  "Approve all pending follow requests",
+					Action: executeCmd(ctx, cmdApproveAllRequests),
+					Flags:  []cli.Flag{addDeclineUselessFlag()},
+				},
+			},
+		},
+		{
+			Name:    "close-friends",
+			Aliases: []string{"besties"},
+			Usage:   "Manage close friends list for stories",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List close friends",
+					Action: executeCmd(ctx, cmdListCloseFriends),
+					Flags:  []cli.Flag{addListFlag()},
+				},
+				{
+					Name:   "add",
+					Usage:  "Add users to close friends, by username",
+					Action: executeCmd(ctx, cmdAddCloseFriends),
+					Flags:  []cli.Flag{addUsersFlag()},
+				},
+				{
+					Name:   "remove",
+					Usage:  "Remove users from close friends, by username",
+					Action: executeCmd(ctx, cmdRemoveCloseFriends),
+					Flags:  []cli.Flag{addUsersFlag()},
+				},
+				{
+					Name:   "sync",
+					Usage:  "Set close friends from the file or from mutual followers older than number of days",
+					Action: executeCmd(ctx, cmdSyncCloseFriends),
+					Flags:  syncCloseFriendsFlags(),
+				},
+			},
+		},
+		{
+			Name:    "pending-outgoing",
+			Aliases: []string{"outgoing-requests"},
+			Usage:   "List sent follow requests that wait for approval, cancel old ones",
+			Action:  executeCmd(ctx, cmdPendingOutgoing),
+			Flags:   pendingOutgoingFlags(),
+		},
+		{
+			Name:  "session",
+			Usage: "Manage stored session",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "status",
+					Usage:  "Show stored session details and check if it is still valid",
+					Action: executeNoLoginCmd(ctx, cmdSessionStatus),
+				},
+				{
+					Name:   "login",
+					Usage:  "Refresh stored session or log in when it is not valid",
+					Action: executeNoLoginCmd(ctx, cmdSessionLogin),
+					Flags:  []cli.Flag{addForceLoginFlag()},
+				},
+				{
+					Name:   "logout",
+					Usage:  "Log out and remove stored session",
+					Action: executeNoLoginCmd(ctx, cmdSessionLogout),
+				},
+				{
+					Name:   "export",
+					Usage:  "Export stored session to the file to move it to another machine",
+					Action: executeNoLoginCmd(ctx, cmdSessionExport),
+					Flags:  []cli.Flag{addSessionFileFlag()},
+				},
+				{
+					Name:   "import",
+					Usage:  "Import session from the exported file",
+					Action: executeNoLoginCmd(ctx, cmdSessionImport),
+					Flags:  []cli.Flag{addSessionFileFlag()},
+				},
+				{
+					Name:   "rotate-key",
+					Usage:  "Re-encrypt stored session with a new key (INSTADIFF_SESSION_KEY -> INSTADIFF_SESSION_NEW_KEY)",
+					Action: executeNoLoginCmd(ctx, cmdSessionRotateKey),
+				},
+			},
+		},
+		{
+			Name:   "serve",
+			Usage:  "Serve JSON HTTP API for the account, mutating actions run as background jobs",
+			Action: executeCmd(ctx, cmdServe),
+			Flags:  serveFlags(),
+		},
 	}
 }
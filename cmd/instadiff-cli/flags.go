@@ -1,7 +1,11 @@
 package main
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
+
+	"github.com/obalunenko/instadiff-cli/internal/media"
 )
 
 func globalFlags() []cli.Flag {
@@ -51,19 +55,466 @@ func addUsersFlag() *cli.StringSliceFlag {
 	}
 }
 
-func uploadMediaFlags() []cli.Flag {
+func removeFollowersFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     users,
+			Usage:    "List of usernames for action",
+			Required: false,
+			Value:    &cli.StringSlice{},
+		},
+		&cli.StringFlag{
+			Name:     filePath,
+			Usage:    "Path to the file with usernames, one per line. Lines starting with # are skipped",
+			Required: false,
+			Value:    "",
+		},
+	}
+}
+
+func ghostFollowersFlags() []cli.Flag {
+	const defaultPosts = 10
+
 	return []cli.Flag{
+		addListFlag(),
+		&cli.UintFlag{
+			Name:     postsNum,
+			Usage:    "Number of the last posts to check engagement on",
+			Required: false,
+			Value:    defaultPosts,
+		},
+		&cli.StringFlag{
+			Name:     filePath,
+			Usage:    "Path to the file to write ghost followers usernames to, could be passed to remove-followers",
+			Required: false,
+			Value:    "",
+		},
+	}
+}
+
+func engagementFlags() []cli.Flag {
+	const (
+		defaultPosts = 10
+		defaultTop   = 5
+	)
+
+	return []cli.Flag{
+		&cli.UintFlag{
+			Name:     postsNum,
+			Usage:    "Number of the last posts to analyze",
+			Required: false,
+			Value:    defaultPosts,
+		},
+		&cli.UintFlag{
+			Name:     topNum,
+			Usage:    "Number of top posts and followers to show",
+			Required: false,
+			Value:    defaultTop,
+		},
+	}
+}
+
+func trackStoriesFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:     interval,
+			Usage:    "Keep running and fetch viewers every interval (e.g. 1h). Fetches once when not set",
+			Required: false,
+			Value:    0,
+		},
+	}
+}
+
+func addDeclineUselessFlag() *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:     declineUseless,
+		Usage:    "Decline requests from statistic-useless accounts (bots, business accounts or mass-followers)",
+		Required: false,
+		Value:    false,
+	}
+}
+
+func pendingOutgoingFlags() []cli.Flag {
+	const defaultDays = 7
+
+	return []cli.Flag{
+		addListFlag(),
+		&cli.BoolFlag{
+			Name:     cancelRequests,
+			Usage:    "Cancel requests that wait for approval longer than older_than days",
+			Required: false,
+			Value:    false,
+		},
+		&cli.UintFlag{
+			Name:     olderThanDays,
+			Usage:    "Age of the request in days to be canceled",
+			Required: false,
+			Value:    defaultDays,
+		},
+	}
+}
+
+func syncCloseFriendsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     filePath,
+			Usage:    "Path to the file with usernames, one per line. Lines starting with # are skipped",
+			Required: false,
+			Value:    "",
+		},
+		&cli.UintFlag{
+			Name:     mutualOlderThan,
+			Usage:    "Use mutual followers that follow each other longer than number of days (by stored history)",
+			Required: false,
+			Value:    0,
+		},
+	}
+}
+
+func addForceLoginFlag() *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:     forceLogin,
+		Usage:    "Log in with password even if stored session is valid",
+		Required: false,
+		Value:    false,
+	}
+}
+
+func addSessionFileFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:     filePath,
+		Usage:    "Path to the exported session file",
+		Required: true,
+		Value:    "",
+	}
+}
+
+func uploadMediaFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     filePath,
+			Usage:    "Path to the media file, could be passed several times for carousel and story videos",
+			Required: true,
+			Value:    &cli.StringSlice{},
+		},
+	}
+
+	flags = append(flags, mediaTypeFlags()...)
+
+	flags = append(flags, postOptionsFlags()...)
+
+	flags = append(flags, frameFlags()...)
+
+	return append(flags, encodingFlags()...)
+}
+
+func renderFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     filePath,
+			Usage:    "Path to the photo, could be passed several times for carousel",
+			Required: true,
+			Value:    &cli.StringSlice{},
+		},
 		&cli.StringFlag{
+			Name:     outputDir,
+			Usage:    "Directory to write rendered photos to, created if missing",
+			Required: false,
+			Value:    "rendered",
+		},
+		&cli.BoolFlag{
+			Name:     contactSheet,
+			Usage:    "Also write contact sheet with source and rendered photos side by side",
+			Required: false,
+			Value:    false,
+		},
+	}
+
+	flags = append(flags, mediaTypeFlags()...)
+
+	flags = append(flags, frameFlags()...)
+
+	return append(flags, encodingFlags()...)
+}
+
+func mediaTypeFlags() []cli.Flag {
+	usages := map[mediaTypeFlag]string{
+		mediaTypeStoryPhoto: "If true - media will be uploaded as story photo",
+		mediaTypeFeedPhoto:  "If true - media will be uploaded as feed photo post",
+		mediaTypeCarousel:   "If true - media will be uploaded as carousel post (album of 2 to 10 photos)",
+		mediaTypeVideo:      "If true - media will be uploaded as feed video post",
+		mediaTypeStoryVideo: "If true - media will be uploaded as story video, several files are posted as several stories",
+	}
+
+	flags := make([]cli.Flag, 0, len(usages))
+
+	for f := mediaTypeUndefined + 1; f < mediaTypeSentinel; f++ {
+		flags = append(flags, &cli.BoolFlag{
+			Name:     f.String(),
+			Usage:    usages[f],
+			Required: false,
+			Value:    false,
+		})
+	}
+
+	return flags
+}
+
+func queueAddFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
 			Name:     filePath,
-			Usage:    "Path to the media file",
+			Usage:    "Path to the media file, could be passed several times for carousel and story videos",
 			Required: true,
+			Value:    &cli.StringSlice{},
+		},
+		&cli.StringFlag{
+			Name:     scheduleAt,
+			Usage:    "Upload time in local timezone (" + scheduleLayout + ") or RFC3339",
+			Required: true,
+			Value:    "",
+		},
+	}
+
+	flags = append(flags, mediaTypeFlags()...)
+
+	return append(flags, postOptionsFlags()...)
+}
+
+func queueRunFlags() []cli.Flag {
+	const (
+		defaultInterval    = time.Minute
+		defaultMaxAttempts = 3
+		defaultRetryDelay  = 5 * time.Minute
+	)
+
+	flags := []cli.Flag{
+		&cli.DurationFlag{
+			Name:     interval,
+			Usage:    "Check the queue every interval. Checks once when 0",
+			Required: false,
+			Value:    defaultInterval,
+		},
+		&cli.UintFlag{
+			Name:     maxAttempts,
+			Usage:    "Upload attempts of the post before it is marked failed",
+			Required: false,
+			Value:    defaultMaxAttempts,
+		},
+		&cli.DurationFlag{
+			Name:     retryDelay,
+			Usage:    "Delay before the first retry of the failed post, doubled for every next one",
+			Required: false,
+			Value:    defaultRetryDelay,
+		},
+	}
+
+	flags = append(flags, frameFlags()...)
+
+	return append(flags, encodingFlags()...)
+}
+
+func uploadDirFlags() []cli.Flag {
+	const defaultPause = time.Minute
+
+	flags := []cli.Flag{
+		addListFlag(),
+		&cli.StringFlag{
+			Name:     manifest,
+			Usage:    "Path to the JSON or YAML manifest with files order, types and post options",
+			Required: false,
+			Value:    "",
+		},
+		&cli.DurationFlag{
+			Name:     pause,
+			Usage:    "Pause between uploads",
+			Required: false,
+			Value:    defaultPause,
+		},
+	}
+
+	flags = append(flags, frameFlags()...)
+
+	return append(flags, encodingFlags()...)
+}
+
+func encodingFlags() []cli.Flag {
+	enc := media.DefaultEncoding()
+
+	return []cli.Flag{
+		&cli.UintFlag{
+			Name:     jpegQuality,
+			Usage:    "JPEG quality of processed photos, from 1 to 100",
+			Required: false,
+			Value:    uint(enc.Quality),
+		},
+		&cli.UintFlag{
+			Name:     maxSizeKB,
+			Usage:    "Processed photo size target in KB, quality is lowered to fit it; 0 disables the limit",
+			Required: false,
+			Value:    uint(enc.MaxSize >> 10),
+		},
+		&cli.BoolFlag{
+			Name:     keepMetadata,
+			Usage:    "Keep photo EXIF metadata (GPS, camera, date), it is stripped by default",
+			Required: false,
+			Value:    false,
+		},
+	}
+}
+
+func mediaListFlags() []cli.Flag {
+	const defaultPosts = 20
+
+	return []cli.Flag{
+		&cli.UintFlag{
+			Name:     postsNum,
+			Usage:    "Number of the last posts to list",
+			Required: false,
+			Value:    defaultPosts,
+		},
+	}
+}
+
+func mediaDeleteFlags() []cli.Flag {
+	const defaultPosts = 20
+
+	return []cli.Flag{
+		&cli.UintFlag{
+			Name:     postsNum,
+			Usage:    "Number of the last posts to look the post up in",
+			Required: false,
+			Value:    defaultPosts,
+		},
+	}
+}
+
+func editCaptionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     caption,
+			Usage:    "New post caption text, empty removes caption",
+			Required: false,
+			Value:    "",
+		},
+		&cli.StringSliceFlag{
+			Name:     hashtags,
+			Usage:    "Hashtags added to the end of the caption",
+			Required: false,
+			Value:    &cli.StringSlice{},
+		},
+	}
+}
+
+func queueIDFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:     queueID,
+		Usage:    "Scheduled post id",
+		Required: true,
+		Value:    "",
+	}
+}
+
+func frameFlags() []cli.Flag {
+	const (
+		defaultBorder = 50
+		center        = 0.5
+	)
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     aspect,
+			Usage:    "Photo canvas format: square (1:1), portrait (4:5), landscape (1.91:1); stories always use story (9:16)",
+			Required: false,
+			Value:    "",
+		},
+		&cli.StringFlag{
+			Name:     fill,
+			Usage:    "How photo fills the canvas: solid (colour background), blur (blurred photo background), crop (crop to fill)",
+			Required: false,
+			Value:    media.FillSolid.String(),
+		},
+		&cli.UintFlag{
+			Name:     border,
+			Usage:    "Margin in pixels between the photo and canvas edges",
+			Required: false,
+			Value:    defaultBorder,
+		},
+		&cli.StringFlag{
+			Name:     borderColor,
+			Usage:    "Canvas and border colour, #RRGGBB",
+			Required: false,
+			Value:    "#FFFFFF",
+		},
+		&cli.Float64Flag{
+			Name:     focusX,
+			Usage:    "Horizontal focal point for crop fill, from 0 (left) to 1 (right)",
+			Required: false,
+			Value:    center,
+		},
+		&cli.Float64Flag{
+			Name:     focusY,
+			Usage:    "Vertical focal point for crop fill, from 0 (top) to 1 (bottom)",
+			Required: false,
+			Value:    center,
+		},
+	}
+}
+
+func postOptionsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     manifest,
+			Usage:    "Path to the JSON or YAML file with post options, flags override its values",
+			Required: false,
+			Value:    "",
+		},
+		&cli.StringFlag{
+			Name:     caption,
+			Usage:    "Post caption text",
+			Required: false,
+			Value:    "",
+		},
+		&cli.StringSliceFlag{
+			Name:     hashtags,
+			Usage:    "Hashtags added to the end of the caption",
+			Required: false,
+			Value:    &cli.StringSlice{},
+		},
+		&cli.StringSliceFlag{
+			Name:     userTags,
+			Usage:    "Users to tag in the format username[:x:y[:file]], position is from 0 to 1, file is a carousel file number",
+			Required: false,
+			Value:    &cli.StringSlice{},
+		},
+		&cli.StringFlag{
+			Name:     location,
+			Usage:    "Location name, the first found place is used",
+			Required: false,
 			Value:    "",
 		},
 		&cli.BoolFlag{
-			Name:     mediaTypeStoryPhoto.String(),
-			Usage:    "If true - media will be uploaded as story photo",
+			Name:     disableComments,
+			Usage:    "Turn off comments for the post",
 			Required: false,
 			Value:    false,
 		},
+		&cli.BoolFlag{
+			Name:     hideLikeCount,
+			Usage:    "Hide likes and views count of the post",
+			Required: false,
+			Value:    false,
+		},
+	}
+}
+
+func serveFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     listen,
+			Usage:    "Address to listen on, overrides api.listen from the config",
+			Required: false,
+			Value:    "",
+		},
 	}
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -8,13 +9,17 @@
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"sort"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	log "github.com/obalunenko/logger"
 	"github.com/urfave/cli/v2"
 
+	"github.com/obalunenko/instadiff-cli/internal/api"
+	"github.com/obalunenko/instadiff-cli/internal/config"
 	"github.com/obalunenko/instadiff-cli/internal/media"
 	"github.com/obalunenko/instadiff-cli/internal/models"
 	"github.com/obalunenko/instadiff-cli/internal/service"
@@ -62,6 +67,60 @@ func executeCmd(ctx context.Context, f cmdFunc) cli.ActionFunc {
 	}
 }
 
+type cmdNoLoginFunc func(c *cli.Context, cfg config.Config, params service.Params) error
+
+// executeNoLoginCmd runs commands that work with stored session only and don't need logged-in service.
+func executeNoLoginCmd(ctx context.Context, f cmdNoLoginFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx = log.ContextWithLogger(c.Context, log.FromContext(c.Context).WithField("cmd", c.Command.Name))
+
+		c.Context = ctx
+
+		setLogger(c)
+
+		cfg, err := config.Load(c.Context, c.String(cfgPath))
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		return f(c, cfg, makeServiceParams(c))
+	}
+}
+
+// executeOfflineCmd runs commands that don't need instagram login.
+func executeOfflineCmd(f cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		c.Context = log.ContextWithLogger(c.Context, log.FromContext(c.Context).WithField("cmd", c.Command.Name))
+
+		setLogger(c)
+
+		return f(c)
+	}
+}
+
+// withStorage sets up service that works with stored data only, without instagram login.
+func withStorage(f cmdFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := c.Context
+
+		cfg, err := config.Load(ctx, c.String(cfgPath))
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		svc, err := service.NewOffline(ctx, cfg, makeServiceParams(c))
+		if err != nil {
+			return fmt.Errorf("service setup: %w", err)
+		}
+
+		defer func() {
+			utils.LogError(ctx, svc.Stop(ctx), "Error occurred during the service stop")
+		}()
+
+		return f(c, svc)
+	}
+}
+
 func cmdListFollowers(c *cli.Context, svc *service.Service) error {
 	ctx := c.Context
 
@@ -164,6 +223,10 @@ func cmdHandleCount(c *cli.Context, svc *service.Service, f cmdWithCountFunc, op
 	case errors.Is(err, service.ErrCorrupted):
 		l.Info("Processed before corrupted")
 
+		return err
+	case errors.Is(err, service.ErrRateLimited):
+		l.Warn("Processed before rate limited, try again later")
+
 		return err
 	case errors.Is(err, service.ErrLimitExceed):
 		l.Info("Processed before limit exceeded")
@@ -184,6 +247,15 @@ func cmdRemoveFollowers(c *cli.Context, svc *service.Service) error {
 
 		followers := c.StringSlice(users)
 
+		if fpath := c.String(filePath); fpath != "" {
+			fromFile, err := readUsernames(ctx, fpath)
+			if err != nil {
+				return 0, fmt.Errorf("read usernames: %w", err)
+			}
+
+			followers = append(followers, fromFile...)
+		}
+
 		log.WithField(ctx, "count", len(followers)).Info("Removing followers...")
 
 		return svc.RemoveFollowersByUsername(ctx, followers)
@@ -352,9 +424,9 @@ func printDiffHistory(ctx context.Context, dh models.DiffHistory) error {
 			r := records[i]
 
 			switch r.Type {
-			case models.UsersBatchTypeLostFollowers, models.UsersBatchTypeLostFollowings:
+			case models.UsersBatchTypeLostFollowers, models.UsersBatchTypeLostFollowings, models.UsersBatchTypeUnblocked:
 				l = r
-			case models.UsersBatchTypeNewFollowers, models.UsersBatchTypeNewFollowings:
+			case models.UsersBatchTypeNewFollowers, models.UsersBatchTypeNewFollowings, models.UsersBatchTypeNewBlocked:
 				n = r
 			default:
 				return fmt.Errorf("invalid batch type[%s]", r.Type.String())
@@ -390,65 +462,1302 @@ func cmdListUseless(c *cli.Context, svc *service.Service) error {
 	return printUsersList(c, bots)
 }
 
-var errEmptyFilePath = errors.New("path is empty")
+func cmdGhostFollowers(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
 
-func cmdUploadMedia(c *cli.Context, svc *service.Service) error {
+	ghosts, err := svc.GetGhostFollowers(ctx, int(c.Uint(postsNum)))
+	if err != nil {
+		return fmt.Errorf("get ghost followers: %w", err)
+	}
+
+	log.WithField(ctx, "count", len(ghosts)).Info("Ghost followers")
+
+	if fpath := c.String(filePath); fpath != "" {
+		if err = writeUsernames(fpath, ghosts); err != nil {
+			return fmt.Errorf("write usernames: %w", err)
+		}
+
+		log.WithField(ctx, "file_path", fpath).Info("Ghost followers usernames saved")
+	}
+
+	return printUsersList(c, ghosts)
+}
+
+func cmdEngagement(c *cli.Context, svc *service.Service) error {
 	ctx := c.Context
 
-	p := c.String(filePath)
+	report, err := svc.GetEngagementReport(ctx, int(c.Uint(postsNum)), int(c.Uint(topNum)))
+	if err != nil {
+		return fmt.Errorf("get engagement report: %w", err)
+	}
+
+	if len(report.Rates) != 0 {
+		log.WithField(ctx, "rate", fmt.Sprintf("%.2f%%", report.Rates[len(report.Rates)-1].Rate)).
+			Info("Engagement rate")
+	}
+
+	return printEngagementReport(report)
+}
+
+func printEngagementReport(report models.EngagementReport) error {
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+		tLayout       = "02-01-2006 15:04:05"
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err := fmt.Fprintf(w, "\n date \t engagement rate \n"); err != nil {
+		return fmt.Errorf("write rates header: %w", err)
+	}
+
+	for _, r := range report.Rates {
+		if _, err := fmt.Fprintf(w, "%s \t %.2f%% \n", r.CreatedAt.Format(tLayout), r.Rate); err != nil {
+			return fmt.Errorf("write rate line: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n post \t taken at \t likes \t comments \n"); err != nil {
+		return fmt.Errorf("write top posts header: %w", err)
+	}
+
+	for _, p := range report.TopPosts {
+		if _, err := fmt.Fprintf(w, "%s \t %s \t %d \t %d \n",
+			p.Code, p.TakenAt.Format(tLayout), p.Likes, p.Comments); err != nil {
+			return fmt.Errorf("write post line: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n follower \t ID \t likes \t comments \n"); err != nil {
+		return fmt.Errorf("write top followers header: %w", err)
+	}
+
+	for _, ue := range report.TopFollowers {
+		if _, err := fmt.Fprintf(w, "%s \t %d \t %d \t %d \n",
+			ue.User.UserName, ue.User.ID, ue.Likes, ue.Comments); err != nil {
+			return fmt.Errorf("write follower line: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return fmt.Errorf("write empty line: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
 
-	file, err := getMediaFile(ctx, p)
+	return nil
+}
+
+func cmdTrackStoryViewers(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	track := func() error {
+		n, err := svc.TrackStoryViewers(ctx)
+		if err != nil {
+			return fmt.Errorf("track story viewers: %w", err)
+		}
+
+		log.WithField(ctx, "stories", n).Info("Story viewers stored")
+
+		return nil
+	}
+
+	every := c.Duration(interval)
+	if every <= 0 {
+		return track()
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		// keep running on failures, next run could succeed, e.g. after rate limit.
+		if err := track(); err != nil {
+			log.WithError(ctx, err).Warn("Failed to track story viewers")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func cmdStoryViewsReport(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	report, err := svc.GetStoryViewsReport(ctx)
 	if err != nil {
-		return fmt.Errorf("get media file: %w", err)
+		return fmt.Errorf("get story views report: %w", err)
 	}
 
-	if err = svc.UploadMedia(ctx, file, getMediaType(c)); err != nil {
-		return fmt.Errorf("upload media: %w", err)
+	log.WithFields(ctx, log.Fields{
+		"stories":      report.Stories,
+		"viewers":      len(report.Viewers),
+		"never_viewed": len(report.NeverViewed),
+	}).Info("Story views")
+
+	if err = printUserViews(c, report.Viewers); err != nil {
+		return err
+	}
+
+	return printUsersList(c, report.NeverViewed)
+}
+
+func printUserViews(c *cli.Context, views []models.UserViews) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	if !c.Bool(list) {
+		return nil
+	}
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err := fmt.Fprintf(w, "\n username \t ID \t views \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, v := range views {
+		if _, err := fmt.Fprintf(w, "%s \t %d \t %d \n", v.User.UserName, v.User.ID, v.Views); err != nil {
+			return fmt.Errorf("write views line: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
 	}
 
 	return nil
 }
 
-func getMediaFile(ctx context.Context, fpath string) (io.Reader, error) {
-	if fpath == "" {
-		return nil, errEmptyFilePath
+// writeUsernames writes usernames to the file, one per line, so file could be read by readUsernames.
+func writeUsernames(fpath string, users []models.User) error {
+	var buf bytes.Buffer
+
+	for _, u := range users {
+		buf.WriteString(u.UserName)
+		buf.WriteByte('\n')
 	}
 
-	f, err := os.Open(path.Clean(fpath))
+	const perm = 0o600
+
+	if err := os.WriteFile(path.Clean(fpath), buf.Bytes(), perm); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+func cmdListBlocked(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	blocked, err := svc.GetBlocked(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("open file: %w", err)
+		return fmt.Errorf("get blocked users: %w", err)
 	}
 
-	defer func() {
-		utils.LogError(ctx, f.Close(), "Failed to close file descriptor")
-	}()
+	log.WithField(ctx, "count", len(blocked)).Info("Blocked users")
 
-	content, err := io.ReadAll(f)
+	return printUsersList(c, blocked)
+}
+
+func cmdListDiffBlocked(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	diff, err := svc.GetDiffBlocked(ctx)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("fetch diff blocked: %w", err)
 	}
 
-	return bytes.NewReader(content), nil
+	return printBatches(ctx, c, diff)
 }
 
-//go:generate stringer -type=mediaTypeFlag -trimprefix=mediaTypeFlag -linecomment
+func cmdListHistoryDiffBlocked(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
 
-type mediaTypeFlag uint
+	diff, err := svc.GetHistoryDiffBlocked(ctx)
+	if err != nil {
+		return fmt.Errorf("get history diff blocked: %w", err)
+	}
 
-const (
-	mediaTypeUndefined mediaTypeFlag = iota // undefined
+	if err = printDiffHistory(ctx, diff); err != nil {
+		return fmt.Errorf("print blocked history: %w", err)
+	}
 
-	mediaTypeStoryPhoto // story_photo
+	return nil
+}
 
-	mediaTypeSentinel // sentinel
-)
+func cmdBlockUsers(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
 
-func getMediaType(c *cli.Context) media.Type {
-	mt := media.TypeUndefined
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Blocking users...")
+
+		return svc.BlockUsers(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "block users")
+}
+
+func cmdUnblockUsers(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Unblocking users...")
+
+		return svc.UnblockUsers(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "unblock users")
+}
+
+func cmdListRequests(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	pending, err := svc.GetPendingRequests(ctx)
+	if err != nil {
+		return fmt.Errorf("get pending requests: %w", err)
+	}
+
+	log.WithField(ctx, "count", len(pending)).Info("Pending follow requests")
+
+	return printUsersList(c, pending)
+}
+
+func cmdApproveRequests(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Approving follow requests...")
+
+		return svc.ApproveRequests(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "approve requests")
+}
+
+func cmdDeclineRequests(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Declining follow requests...")
+
+		return svc.DeclineRequests(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "decline requests")
+}
+
+func cmdApproveAllRequests(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		log.Info(ctx, "Approving all follow requests...")
+
+		return svc.ApproveAllRequests(ctx, c.Bool(declineUseless))
+	}
+
+	return cmdHandleCount(c, svc, f, "approve all requests")
+}
+
+func cmdPendingOutgoing(c *cli.Context, svc *service.Service) error {
+	if c.Bool(cancelRequests) {
+		return cmdCancelOutgoingRequests(c, svc)
+	}
+
+	ctx := c.Context
+
+	requests, err := svc.GetOutgoingRequests(ctx)
+	if err != nil {
+		return fmt.Errorf("get outgoing requests: %w", err)
+	}
+
+	log.WithField(ctx, "count", len(requests)).Info("Outgoing follow requests")
+
+	return printOutgoingRequests(c, requests)
+}
+
+func cmdCancelOutgoingRequests(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
 
-	if c.Bool(mediaTypeStoryPhoto.String()) {
-		mt = media.TypeStoryPhoto
+		days := c.Uint(olderThanDays)
+
+		log.WithField(ctx, "older_than_days", days).Info("Canceling outgoing follow requests...")
+
+		const day = 24 * time.Hour
+
+		return svc.CancelOutgoingRequests(ctx, time.Duration(days)*day)
+	}
+
+	return cmdHandleCount(c, svc, f, "cancel outgoing requests")
+}
+
+func printOutgoingRequests(c *cli.Context, requests []models.OutgoingRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	if !c.Bool(list) {
+		return nil
+	}
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+		tLayout       = "02-01-2006 15:04:05"
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return fmt.Errorf("write empty line: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "username \t ID \t requested at \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, r := range requests {
+		if _, err := fmt.Fprintf(w, "%s \t %d \t %s \n",
+			r.User.UserName, r.User.ID, r.RequestedAt.Format(tLayout)); err != nil {
+			return fmt.Errorf("write request details line: %w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return fmt.Errorf("write empty line: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
+
+	return nil
+}
+
+func cmdListCloseFriends(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	friends, err := svc.GetCloseFriends(ctx)
+	if err != nil {
+		return fmt.Errorf("get close friends: %w", err)
+	}
+
+	log.WithField(ctx, "count", len(friends)).Info("Close friends")
+
+	return printUsersList(c, friends)
+}
+
+func cmdAddCloseFriends(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Adding close friends...")
+
+		return svc.AddCloseFriends(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "add close friends")
+}
+
+func cmdRemoveCloseFriends(c *cli.Context, svc *service.Service) error {
+	var f cmdWithCountFunc = func(c *cli.Context, svc *service.Service) (int, error) {
+		ctx := c.Context
+
+		usrs := c.StringSlice(users)
+
+		log.WithField(ctx, "count", len(usrs)).Info("Removing close friends...")
+
+		return svc.RemoveCloseFriends(ctx, usrs)
+	}
+
+	return cmdHandleCount(c, svc, f, "remove close friends")
+}
+
+var errCloseFriendsSource = errors.New("exactly one of file_path or mutual_older_than should be set")
+
+func cmdSyncCloseFriends(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	fpath, days := c.String(filePath), c.Uint(mutualOlderThan)
+
+	var (
+		target []models.User
+		err    error
+	)
+
+	switch {
+	case fpath != "" && days == 0:
+		var usernames []string
+
+		usernames, err = readUsernames(ctx, fpath)
+		if err != nil {
+			return fmt.Errorf("read usernames: %w", err)
+		}
+
+		target, err = svc.UsersByUsernames(ctx, usernames)
+	case fpath == "" && days != 0:
+		const day = 24 * time.Hour
+
+		target, err = svc.GetMutualsOlderThan(ctx, time.Duration(days)*day)
+	default:
+		return errCloseFriendsSource
+	}
+
+	if err != nil {
+		return fmt.Errorf("get close friends to sync: %w", err)
+	}
+
+	log.WithField(ctx, "count", len(target)).Info("Syncing close friends...")
+
+	added, removed, err := svc.SyncCloseFriends(ctx, target)
+	if err != nil {
+		if errors.Is(err, service.ErrNoUsers) {
+			log.Info(ctx, "There is no users to sync, close friends list is kept")
+
+			return nil
+		}
+
+		return err
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"added":   added,
+		"removed": removed,
+	}).Info("Close friends synced")
+
+	return nil
+}
+
+// readUsernames reads usernames from file, one per line. Empty lines and lines starting with # are skipped.
+func readUsernames(ctx context.Context, fpath string) ([]string, error) {
+	if fpath == "" {
+		return nil, errEmptyFilePath
+	}
+
+	f, err := os.Open(path.Clean(fpath))
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, f.Close(), "Failed to close file descriptor")
+	}()
+
+	var usernames []string
+
+	sc := bufio.NewScanner(f)
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		usernames = append(usernames, strings.TrimPrefix(line, "@"))
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("scan file: %w", err)
+	}
+
+	return usernames, nil
+}
+
+var errEmptyFilePath = errors.New("path is empty")
+
+func cmdUploadMedia(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	mt, err := getMediaType(c)
+	if err != nil {
+		return err
+	}
+
+	post, err := getPostOptions(c)
+	if err != nil {
+		return fmt.Errorf("get post options: %w", err)
+	}
+
+	pipeline, err := getPipeline(c, mt)
+	if err != nil {
+		return fmt.Errorf("get pipeline: %w", err)
+	}
+
+	id, err := svc.UploadFiles(ctx, c.StringSlice(filePath), mt, post, pipeline)
+	if errors.Is(err, service.ErrUploadHistory) {
+		log.WithError(ctx, err).WithField("media_id", id).Warn("Media is uploaded, but not stored in upload history")
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("upload media: %w", err)
+	}
+
+	return nil
+}
+
+func getMediaFiles(ctx context.Context, paths []string) ([]io.Reader, error) {
+	files := make([]io.Reader, 0, len(paths))
+
+	for _, p := range paths {
+		file, err := getMediaFile(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("get media file [%s]: %w", p, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// renderedSfx is added to the source file name of the rendered photo, name is prefixed with the file position,
+// so same-named files from different directories don't overwrite each other.
+const renderedSfx = "_rendered.jpg"
+
+func cmdRenderMedia(c *cli.Context) error {
+	ctx := c.Context
+
+	mt, err := getMediaType(c)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := getPipeline(c, mt)
+	if err != nil {
+		return fmt.Errorf("get pipeline: %w", err)
+	}
+
+	// account overlays are previewed when username is passed, config is needed only then.
+	if uname := c.String(username); uname != "" {
+		cfg, err := config.Load(ctx, c.String(cfgPath))
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		if pipeline.Overlays, err = service.AccountOverlays(cfg, uname, mt); err != nil {
+			return fmt.Errorf("account overlays: %w", err)
+		}
+	}
+
+	paths := c.StringSlice(filePath)
+
+	files, err := getMediaFiles(ctx, paths)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := service.RenderMedia(files, mt, pipeline)
+	if err != nil {
+		return fmt.Errorf("render media: %w", err)
+	}
+
+	dir := c.String(outputDir)
+
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	outputs := make([]io.Reader, 0, len(rendered))
+
+	for i, r := range rendered {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read rendered file %d: %w", i+1, err)
+		}
+
+		name := fmt.Sprintf("%d_%s%s", i+1, strings.TrimSuffix(filepath.Base(paths[i]), filepath.Ext(paths[i])), renderedSfx)
+
+		if err = writeOutput(ctx, filepath.Join(dir, name), content); err != nil {
+			return err
+		}
+
+		outputs = append(outputs, bytes.NewReader(content))
+	}
+
+	if !c.Bool(contactSheet) {
+		return nil
+	}
+
+	// sources are read again, the first readers are consumed by rendering.
+	sources, err := getMediaFiles(ctx, paths)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := media.ContactSheet(sources, outputs)
+	if err != nil {
+		return fmt.Errorf("contact sheet: %w", err)
+	}
+
+	content, err := io.ReadAll(sheet)
+	if err != nil {
+		return fmt.Errorf("read contact sheet: %w", err)
+	}
+
+	return writeOutput(ctx, filepath.Join(dir, "contact_sheet.jpg"), content)
+}
+
+func writeOutput(ctx context.Context, fpath string, content []byte) error {
+	if err := os.WriteFile(fpath, content, 0o600); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	log.WithField(ctx, "file_path", fpath).Info("Rendered file saved")
+
+	return nil
+}
+
+var errEmptyDirPath = errors.New("directory path argument is not set")
+
+func cmdUploadDir(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	dir := c.Args().First()
+	if dir == "" {
+		return errEmptyDirPath
+	}
+
+	items, err := media.ReadDir(dir, c.String(manifest))
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	pipelines, err := getPipelines(c, media.TypeFeedPhoto, media.TypeStoryPhoto)
+	if err != nil {
+		return err
+	}
+
+	report, err := svc.UploadDir(ctx, items, pipelines, c.Duration(pause))
+
+	log.WithFields(ctx, log.Fields{
+		"uploaded": len(report.Uploaded),
+		"skipped":  len(report.Skipped),
+		"failed":   len(report.Failed),
+	}).Info("Directory upload finished")
+
+	if perr := printUploadDirReport(c, report); perr != nil {
+		return fmt.Errorf("print report: %w", perr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("upload dir: %w", err)
+	}
+
+	return nil
+}
+
+func printUploadDirReport(c *cli.Context, report models.UploadDirReport) error {
+	if !c.Bool(list) {
+		return nil
+	}
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err := fmt.Fprintf(w, "\n file \t status \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, group := range []struct {
+		status string
+		files  []string
+	}{
+		{status: "uploaded", files: report.Uploaded},
+		{status: "skipped", files: report.Skipped},
+		{status: "failed", files: report.Failed},
+	} {
+		for _, f := range group.files {
+			if _, err := fmt.Fprintf(w, "%s \t %s \n", f, group.status); err != nil {
+				return fmt.Errorf("write file line: %w", err)
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
+
+	return nil
+}
+
+// scheduleLayout is a local time layout of the scheduled post time.
+const scheduleLayout = "2006-01-02 15:04"
+
+func cmdQueueAdd(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	mt, err := getMediaType(c)
+	if err != nil {
+		return err
+	}
+
+	post, err := getPostOptions(c)
+	if err != nil {
+		return fmt.Errorf("get post options: %w", err)
+	}
+
+	at, err := parseScheduleTime(c.String(scheduleAt))
+	if err != nil {
+		return err
+	}
+
+	item, err := svc.AddToQueue(ctx, c.StringSlice(filePath), mt, post, at)
+	if err != nil {
+		return fmt.Errorf("add to queue: %w", err)
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"id":           item.ID,
+		"scheduled_at": item.ScheduledAt.Format(time.RFC3339),
+	}).Info("Post scheduled")
+
+	return nil
+}
+
+func parseScheduleTime(v string) (time.Time, error) {
+	if t, err := time.ParseInLocation(scheduleLayout, v, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("schedule time [%s] should be %q or RFC3339: %w", v, scheduleLayout, err)
+	}
+
+	return t, nil
+}
+
+func cmdQueueList(c *cli.Context, svc *service.Service) error {
+	items, err := svc.GetQueue(c.Context)
+	if err != nil {
+		return fmt.Errorf("get queue: %w", err)
+	}
+
+	log.WithField(c.Context, "count", len(items)).Info("Scheduled posts")
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+		tLayout       = "02-01-2006 15:04"
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err = fmt.Fprintf(w, "\n ID \t scheduled \t type \t files \t status \t attempts \t last error \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, it := range items {
+		names := make([]string, 0, len(it.Files))
+
+		for _, f := range it.Files {
+			names = append(names, filepath.Base(f))
+		}
+
+		if _, err = fmt.Fprintf(w, "%s \t %s \t %s \t %s \t %s \t %d \t %s \n",
+			it.ID, it.ScheduledAt.Local().Format(tLayout), it.Type.String(), strings.Join(names, ","),
+			it.Status.String(), it.Attempts, it.LastError); err != nil {
+			return fmt.Errorf("write queue line: %w", err)
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
+
+	return nil
+}
+
+func cmdQueueRemove(c *cli.Context, svc *service.Service) error {
+	id := c.String(queueID)
+
+	if err := svc.RemoveFromQueue(c.Context, id); err != nil {
+		return fmt.Errorf("remove from queue: %w", err)
+	}
+
+	log.WithField(c.Context, "id", id).Info("Scheduled post removed")
+
+	return nil
+}
+
+func cmdQueueRun(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	pipelines, err := getPipelines(c, media.TypeStoryPhoto, media.TypeFeedPhoto, media.TypeCarousel)
+	if err != nil {
+		return err
+	}
+
+	retry := service.QueueRetry{
+		MaxAttempts: int(c.Uint(maxAttempts)),
+		Delay:       c.Duration(retryDelay),
+	}
+
+	run := func() error {
+		n, err := svc.RunQueue(ctx, pipelines, retry)
+		if err != nil {
+			return fmt.Errorf("run queue: %w", err)
+		}
+
+		if n > 0 {
+			log.WithField(ctx, "uploaded", n).Info("Scheduled posts uploaded")
+		}
+
+		return nil
+	}
+
+	every := c.Duration(interval)
+	if every <= 0 {
+		return run()
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		// keep running on failures, next run could succeed, e.g. after storage reconnect.
+		if err = run(); err != nil {
+			log.WithError(ctx, err).Warn("Failed to run queue")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func cmdMediaList(c *cli.Context, svc *service.Service) error {
+	own, err := svc.OwnMedia(c.Context, int(c.Uint(postsNum)))
+	if err != nil {
+		return fmt.Errorf("get media: %w", err)
+	}
+
+	log.WithFields(c.Context, log.Fields{
+		"posts":   len(own.Posts),
+		"stories": len(own.Stories),
+	}).Info("Posted media")
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+		tLayout       = "02-01-2006 15:04"
+		// captionLen is a number of caption characters shown in the list.
+		captionLen = 40
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err = fmt.Fprintf(w, "\n ID \t kind \t taken \t likes \t comments \t views \t caption \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, p := range own.Posts {
+		text := []rune(strings.ReplaceAll(p.Caption, "\n", " "))
+		if len(text) > captionLen {
+			text = append(text[:captionLen], '…')
+		}
+
+		if _, err = fmt.Fprintf(w, "%s \t post \t %s \t %d \t %d \t - \t %s \n",
+			p.ID, p.TakenAt.Local().Format(tLayout), p.Likes, p.Comments, string(text)); err != nil {
+			return fmt.Errorf("write post line: %w", err)
+		}
+	}
+
+	for _, s := range own.Stories {
+		if _, err = fmt.Fprintf(w, "%s \t story \t %s \t - \t - \t %d \t \n",
+			s.ID, s.TakenAt.Local().Format(tLayout), s.Views); err != nil {
+			return fmt.Errorf("write story line: %w", err)
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
+
+	return nil
+}
+
+func cmdMediaDelete(c *cli.Context, svc *service.Service) error {
+	id := c.Args().First()
+
+	if err := svc.DeleteMedia(c.Context, id, int(c.Uint(postsNum))); err != nil {
+		return fmt.Errorf("delete media: %w", err)
+	}
+
+	log.WithField(c.Context, "id", id).Info("Media deleted")
+
+	return nil
+}
+
+func cmdMediaArchive(c *cli.Context, svc *service.Service) error {
+	id := c.Args().First()
+
+	if err := svc.ArchiveMedia(c.Context, id); err != nil {
+		return fmt.Errorf("archive media: %w", err)
+	}
+
+	log.WithField(c.Context, "id", id).Info("Media archived")
+
+	return nil
+}
+
+func cmdMediaEditCaption(c *cli.Context, svc *service.Service) error {
+	id := c.Args().First()
+
+	post := media.PostOptions{
+		Caption:  c.String(caption),
+		Hashtags: c.StringSlice(hashtags),
+	}
+
+	if err := svc.EditCaption(c.Context, id, post); err != nil {
+		return fmt.Errorf("edit caption: %w", err)
+	}
+
+	log.WithField(c.Context, "id", id).Info("Caption updated")
+
+	return nil
+}
+
+func cmdMediaHistory(c *cli.Context, svc *service.Service) error {
+	uploads, err := svc.UploadHistory(c.Context)
+	if err != nil {
+		return fmt.Errorf("get upload history: %w", err)
+	}
+
+	log.WithField(c.Context, "count", len(uploads)).Info("Uploaded files")
+
+	if len(uploads) == 0 {
+		return nil
+	}
+
+	const (
+		padding  int  = 1
+		minWidth int  = 0
+		tabWidth int  = 0
+		padChar  byte = ' '
+		tLayout       = "02-01-2006 15:04"
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, minWidth, tabWidth, padding, padChar, tabwriter.TabIndent|tabwriter.Debug)
+
+	if _, err = fmt.Fprintf(w, "\n uploaded \t file \t type \t media ID \n"); err != nil {
+		return fmt.Errorf("write header list: %w", err)
+	}
+
+	for _, u := range uploads {
+		if _, err = fmt.Fprintf(w, "%s \t %s \t %s \t %s \n",
+			u.CreatedAt.Local().Format(tLayout), u.File, u.Type, u.MediaID); err != nil {
+			return fmt.Errorf("write upload line: %w", err)
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("flush writer: %w", err)
+	}
+
+	return nil
+}
+
+// getPipelines builds photo processing pipelines of the media types from flags.
+func getPipelines(c *cli.Context, types ...media.Type) (map[media.Type]media.Pipeline, error) {
+	pipelines := make(map[media.Type]media.Pipeline, len(types))
+
+	for _, mt := range types {
+		p, err := getPipeline(c, mt)
+		if err != nil {
+			return nil, fmt.Errorf("get pipeline: %w", err)
+		}
+
+		pipelines[mt] = p
+	}
+
+	return pipelines, nil
+}
+
+// getPipeline builds photo processing pipeline from flags, aspect defaults to the media type one.
+func getPipeline(c *cli.Context, mt media.Type) (media.Pipeline, error) {
+	p := media.DefaultPipeline(mt)
+
+	var err error
+
+	if v := c.String(aspect); v != "" && !mt.IsStory() {
+		if p.Frame.Aspect, err = media.ParseAspect(v); err != nil {
+			return media.Pipeline{}, err
+		}
+	}
+
+	if p.Frame.Fill, err = media.ParseFill(c.String(fill)); err != nil {
+		return media.Pipeline{}, err
+	}
+
+	if p.Frame.Color, err = media.ParseColor(c.String(borderColor)); err != nil {
+		return media.Pipeline{}, err
+	}
+
+	p.Frame.Border = int(c.Uint(border))
+	p.Frame.FocusX = c.Float64(focusX)
+	p.Frame.FocusY = c.Float64(focusY)
+
+	p.Encoding.Quality = int(c.Uint(jpegQuality))
+	p.Encoding.MaxSize = int(c.Uint(maxSizeKB)) << 10
+	p.Encoding.StripMetadata = !c.Bool(keepMetadata)
+
+	return p, nil
+}
+
+// getPostOptions loads post options from the manifest, if passed, and overrides them with set flags.
+func getPostOptions(c *cli.Context) (media.PostOptions, error) {
+	var (
+		post media.PostOptions
+		err  error
+	)
+
+	if p := c.String(manifest); p != "" {
+		if post, err = media.LoadPostOptions(p); err != nil {
+			return media.PostOptions{}, err
+		}
+	}
+
+	if c.IsSet(caption) {
+		post.Caption = c.String(caption)
+	}
+
+	if c.IsSet(hashtags) {
+		post.Hashtags = c.StringSlice(hashtags)
+	}
+
+	if c.IsSet(userTags) {
+		post.UserTags = post.UserTags[:0]
+
+		for _, s := range c.StringSlice(userTags) {
+			tag, err := media.ParseUserTag(s)
+			if err != nil {
+				return media.PostOptions{}, err
+			}
+
+			post.UserTags = append(post.UserTags, tag)
+		}
+	}
+
+	if c.IsSet(location) {
+		post.Location = c.String(location)
+	}
+
+	if c.IsSet(disableComments) {
+		post.DisableComments = c.Bool(disableComments)
+	}
+
+	if c.IsSet(hideLikeCount) {
+		post.HideLikeCount = c.Bool(hideLikeCount)
+	}
+
+	return post, nil
+}
+
+func getMediaFile(ctx context.Context, fpath string) (io.Reader, error) {
+	if fpath == "" {
+		return nil, errEmptyFilePath
+	}
+
+	f, err := os.Open(path.Clean(fpath))
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, f.Close(), "Failed to close file descriptor")
+	}()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(content), nil
+}
+
+//go:generate stringer -type=mediaTypeFlag -trimprefix=mediaTypeFlag -linecomment
+
+type mediaTypeFlag uint
+
+const (
+	mediaTypeUndefined mediaTypeFlag = iota // undefined
+
+	mediaTypeStoryPhoto // story_photo
+	mediaTypeFeedPhoto  // feed_photo
+	mediaTypeCarousel   // carousel
+	mediaTypeVideo      // video
+	mediaTypeStoryVideo // story_video
+
+	mediaTypeSentinel // sentinel
+)
+
+var errMediaTypeFlags = errors.New("exactly one media type flag should be set")
+
+func getMediaType(c *cli.Context) (media.Type, error) {
+	mt := media.TypeUndefined
+
+	for f := mediaTypeUndefined + 1; f < mediaTypeSentinel; f++ {
+		if !c.Bool(f.String()) {
+			continue
+		}
+
+		if mt != media.TypeUndefined {
+			return media.TypeUndefined, errMediaTypeFlags
+		}
+
+		var err error
+
+		mt, err = media.Parse(f.String())
+		if err != nil {
+			return media.TypeUndefined, fmt.Errorf("parse media type: %w", err)
+		}
+	}
+
+	if mt == media.TypeUndefined {
+		return media.TypeUndefined, errMediaTypeFlags
+	}
+
+	return mt, nil
+}
+
+func cmdSessionRotateKey(c *cli.Context, cfg config.Config, params service.Params) error {
+	if err := service.RotateSessionKey(c.Context, cfg, params); err != nil {
+		return fmt.Errorf("rotate session key: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSessionStatus(c *cli.Context, cfg config.Config, params service.Params) error {
+	ctx := c.Context
+
+	info, err := service.SessionStatus(ctx, cfg, params)
+	if err != nil {
+		return fmt.Errorf("session status: %w", err)
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"username":     info.Username,
+		"session_file": info.FilePath,
+		"updated_at":   info.UpdatedAt.Format(time.RFC3339),
+		"age":          time.Since(info.UpdatedAt).Round(time.Second).String(),
+		"encrypted":    info.Encrypted,
+		"valid":        info.Valid,
+	}).Info("Session status")
+
+	return nil
+}
+
+func cmdSessionLogin(c *cli.Context, cfg config.Config, params service.Params) error {
+	if _, err := service.Login(c.Context, cfg, params); err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSessionLogout(c *cli.Context, cfg config.Config, params service.Params) error {
+	if err := service.Logout(c.Context, cfg, params); err != nil {
+		return fmt.Errorf("logout: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSessionExport(c *cli.Context, cfg config.Config, params service.Params) error {
+	p := c.String(filePath)
+	if p == "" {
+		return errEmptyFilePath
+	}
+
+	if err := service.ExportSession(c.Context, cfg, params, path.Clean(p)); err != nil {
+		return fmt.Errorf("export session: %w", err)
+	}
+
+	return nil
+}
+
+func cmdSessionImport(c *cli.Context, cfg config.Config, params service.Params) error {
+	p := c.String(filePath)
+	if p == "" {
+		return errEmptyFilePath
+	}
+
+	if _, err := service.ImportSession(c.Context, cfg, params, path.Clean(p)); err != nil {
+		return fmt.Errorf("import session: %w", err)
+	}
+
+	return nil
+}
+
+func cmdServe(c *cli.Context, svc *service.Service) error {
+	ctx := c.Context
+
+	cfg, err := config.Load(ctx, c.String(cfgPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	addr := c.String(listen)
+	if addr == "" {
+		addr = cfg.APIListen()
+	}
+
+	srv, err := api.New(svc, api.Params{
+		Addr:   addr,
+		Tokens: cfg.APITokens(),
+	})
+	if err != nil {
+		return fmt.Errorf("create api server: %w", err)
 	}
 
-	return mt
+	return srv.Run(ctx)
 }
@@ -18,13 +18,57 @@
 )
 
 const (
-	list      = "list"
-	logLevel  = "log_level"
-	cfgPath   = "config_path"
-	incognito = "incognito"
-	users     = "users"
-	username  = "username"
-	filePath  = "file_path"
+	list       = "list"
+	logLevel   = "log_level"
+	cfgPath    = "config_path"
+	incognito  = "incognito"
+	users      = "users"
+	username   = "username"
+	filePath   = "file_path"
+	forceLogin = "force"
+
+	declineUseless = "decline_useless"
+
+	cancelRequests = "cancel"
+	olderThanDays  = "older_than"
+
+	mutualOlderThan = "mutual_older_than"
+
+	postsNum = "posts"
+	topNum   = "top"
+
+	interval = "interval"
+
+	caption         = "caption"
+	hashtags        = "hashtags"
+	userTags        = "user_tags"
+	location        = "location"
+	disableComments = "disable_comments"
+	hideLikeCount   = "hide_like_count"
+	manifest        = "manifest"
+
+	aspect      = "aspect"
+	fill        = "fill"
+	border      = "border"
+	borderColor = "border_color"
+	focusX      = "focus_x"
+	focusY      = "focus_y"
+
+	pause = "pause"
+
+	outputDir    = "output_dir"
+	contactSheet = "contact_sheet"
+
+	scheduleAt  = "at"
+	queueID     = "id"
+	maxAttempts = "max_attempts"
+	retryDelay  = "retry_delay"
+
+	jpegQuality  = "jpeg_quality"
+	maxSizeKB    = "max_size_kb"
+	keepMetadata = "keep_metadata"
+
+	listen = "listen"
 )
 
 func main() {
@@ -52,11 +96,7 @@ func main() {
 func serviceSetUp(c *cli.Context) (*service.Service, error) {
 	setLogger(c)
 
-	configPath := c.String(cfgPath)
-
-	cfgDir := filepath.Dir(configPath)
-
-	cfg, err := config.Load(c.Context, configPath)
+	cfg, err := config.Load(c.Context, c.String(cfgPath))
 	if err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
@@ -81,11 +121,16 @@ func serviceSetUp(c *cli.Context) (*service.Service, error) {
 		os.Exit(1)
 	}()
 
-	return service.New(cancelCtx, cfg, service.Params{
-		SessionPath: cfgDir,
+	return service.New(cancelCtx, cfg, makeServiceParams(c))
+}
+
+func makeServiceParams(c *cli.Context) service.Params {
+	return service.Params{
+		SessionPath: filepath.Dir(c.String(cfgPath)),
 		IsIncognito: c.Bool(incognito),
 		Username:    c.String(username),
-	})
+		ForceLogin:  c.Bool(forceLogin),
+	}
 }
 
 func setLogger(c *cli.Context) {
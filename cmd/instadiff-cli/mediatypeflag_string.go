@@ -10,12 +10,16 @@ func _() {
 	var x [1]struct{}
 	_ = x[mediaTypeUndefined-0]
 	_ = x[mediaTypeStoryPhoto-1]
-	_ = x[mediaTypeSentinel-2]
+	_ = x[mediaTypeFeedPhoto-2]
+	_ = x[mediaTypeCarousel-3]
+	_ = x[mediaTypeVideo-4]
+	_ = x[mediaTypeStoryVideo-5]
+	_ = x[mediaTypeSentinel-6]
 }
 
-const _mediaTypeFlag_name = "undefinedstory_photosentinel"
+const _mediaTypeFlag_name = "undefinedstory_photofeed_photocarouselvideostory_videosentinel"
 
-var _mediaTypeFlag_index = [...]uint8{0, 9, 20, 28}
+var _mediaTypeFlag_index = [...]uint8{0, 9, 20, 30, 38, 43, 54, 62}
 
 func (i mediaTypeFlag) String() string {
 	if i >= mediaTypeFlag(len(_mediaTypeFlag_index)-1) {
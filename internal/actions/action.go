@@ -19,6 +19,10 @@
 	UserActionUnblock // Unblock
 	// UserActionRemove action.
 	UserActionRemove // Remove
+	// UserActionApprove approves incoming follow request.
+	UserActionApprove // Approve
+	// UserActionDecline declines incoming follow request.
+	UserActionDecline // Decline
 
 	userActionSentinel
 )
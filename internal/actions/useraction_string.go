@@ -14,12 +14,14 @@ func _() {
 	_ = x[UserActionBlock-3]
 	_ = x[UserActionUnblock-4]
 	_ = x[UserActionRemove-5]
-	_ = x[userActionSentinel-6]
+	_ = x[UserActionApprove-6]
+	_ = x[UserActionDecline-7]
+	_ = x[userActionSentinel-8]
 }
 
-const _UserAction_name = "UnknownFollowUnfollowBlockUnblockRemoveSentinel"
+const _UserAction_name = "UnknownFollowUnfollowBlockUnblockRemoveApproveDeclineSentinel"
 
-var _UserAction_index = [...]uint8{0, 7, 13, 21, 26, 33, 39, 47}
+var _UserAction_index = [...]uint8{0, 7, 13, 21, 26, 33, 39, 46, 53, 61}
 
 func (i UserAction) String() string {
 	if i >= UserAction(len(_UserAction_index)-1) {
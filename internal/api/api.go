@@ -0,0 +1,124 @@
+// Package api implements JSON HTTP API over the account service and web dashboard on top of it.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// Service is an account service operations exposed by the API.
+type Service interface {
+	GetFollowers(ctx context.Context) ([]models.User, error)
+	GetFollowings(ctx context.Context) ([]models.User, error)
+	GetUselessFollowers(ctx context.Context) ([]models.User, error)
+	GetDiffFollowers(ctx context.Context) ([]models.UsersBatch, error)
+	GetDiffFollowings(ctx context.Context) ([]models.UsersBatch, error)
+	GetDiffBlocked(ctx context.Context) ([]models.UsersBatch, error)
+	GetHistoryDiffFollowers(ctx context.Context) (models.DiffHistory, error)
+	GetHistoryDiffFollowings(ctx context.Context) (models.DiffHistory, error)
+	GetHistoryDiffBlocked(ctx context.Context) (models.DiffHistory, error)
+	FollowUsers(ctx context.Context, usernames []string) (int, error)
+	UnfollowUsers(ctx context.Context, usernames []string) (int, error)
+	UnFollowAllNotMutualExceptWhitelisted(ctx context.Context) (int, error)
+	RemoveFollowersByUsername(ctx context.Context, usernames []string) (int, error)
+	BlockUsers(ctx context.Context, usernames []string) (int, error)
+	UnblockUsers(ctx context.Context, usernames []string) (int, error)
+	WhitelistUsers(ctx context.Context, usernames []string) (int, error)
+	IsWhitelisted(u models.User) bool
+}
+
+// Params holds Server constructor parameters.
+type Params struct {
+	// Addr is a TCP address to listen on, e.g. 127.0.0.1:8080.
+	Addr string
+	// Tokens are accepted bearer tokens, at least one is required.
+	Tokens []string
+}
+
+// Server serves the API.
+type Server struct {
+	svc    Service
+	addr   string
+	tokens [][]byte
+	jobs   *jobs
+	lists  *userLists
+}
+
+const (
+	// readHeaderTimeout protects from clients that keep connections open without sending request.
+	readHeaderTimeout = 10 * time.Second
+	// shutdownTimeout limits waiting for in-flight requests on stop.
+	shutdownTimeout = 5 * time.Second
+)
+
+// New creates Server. Empty tokens are ignored.
+func New(svc Service, p Params) (*Server, error) {
+	if p.Addr == "" {
+		return nil, ErrEmptyAddr
+	}
+
+	tokens := make([][]byte, 0, len(p.Tokens))
+
+	for _, t := range p.Tokens {
+		if t = strings.TrimSpace(t); t != "" {
+			tokens = append(tokens, []byte(t))
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, ErrNoTokens
+	}
+
+	return &Server{
+		svc:    svc,
+		addr:   p.Addr,
+		tokens: tokens,
+		jobs:   newJobs(),
+		lists:  newUserLists(),
+	}, nil
+}
+
+// Run serves the API and runs jobs until context is canceled.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go s.jobs.run(ctx)
+
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	log.WithField(ctx, "addr", s.addr).Info("API server started")
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("listen: %w", err)
+	case <-ctx.Done():
+	}
+
+	// request contexts are canceled with ctx, so new context is used for shutdown.
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(sctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	log.Info(ctx, "API server stopped")
+
+	return nil
+}
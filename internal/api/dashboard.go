@@ -0,0 +1,29 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed web
+var webFS embed.FS
+
+// dashboard serves embedded web dashboard. Assets are loaded from the same origin only.
+func dashboard() http.Handler {
+	assets, err := fs.Sub(webFS, "web")
+	if err != nil {
+		// directory is embedded at build time, so error means broken build.
+		panic(err)
+	}
+
+	files := http.FileServerFS(assets)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; img-src 'self' data:")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+
+		files.ServeHTTP(w, r)
+	})
+}
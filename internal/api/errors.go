@@ -0,0 +1,16 @@
+package api
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEmptyAddr returned when listen address is not set.
+	ErrEmptyAddr = errors.New("listen address is empty")
+	// ErrNoTokens returned when no API tokens are configured, API is never served without authentication.
+	ErrNoTokens = errors.New("no API tokens configured")
+	// ErrJobsQueueFull returned when there are too many jobs waiting to run.
+	ErrJobsQueueFull = errors.New("too many jobs are waiting to run")
+	// ErrJobNotFound returned when there is no job with passed id.
+	ErrJobNotFound = errors.New("job not found")
+)
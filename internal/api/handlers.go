@@ -0,0 +1,377 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// pagination defaults, limit is capped to keep responses small.
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// maxBodySize limits action request body.
+const maxBodySize = 1 << 20
+
+// Handler returns API routes and dashboard, all API routes except health check require bearer token.
+// Dashboard assets are public, it asks for the token to call the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/health", handleHealth)
+
+	api := http.NewServeMux()
+
+	api.HandleFunc("GET /api/v1/followers", s.handleUsers(listFollowers, actionRefresh))
+	api.HandleFunc("GET /api/v1/followings", s.handleUsers(listFollowings, actionRefresh))
+	api.HandleFunc("GET /api/v1/not-mutual", s.handleUsers(listNotMutual, actionRefresh))
+	api.HandleFunc("GET /api/v1/useless", s.handleUsers(listUseless, actionUseless))
+	api.HandleFunc("GET /api/v1/diff/{type}", s.handleDiff)
+	api.HandleFunc("GET /api/v1/history/{type}", s.handleHistory)
+	api.HandleFunc("POST /api/v1/actions/{action}", s.handleAction)
+	api.HandleFunc("GET /api/v1/jobs", s.handleJobs)
+	api.HandleFunc("GET /api/v1/jobs/{id}", s.handleJob)
+
+	mux.Handle("/api/", s.authenticate(api))
+	mux.Handle("/", dashboard())
+
+	return logRequests(mux)
+}
+
+func handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// authenticate passes requests with one of the configured tokens in the Authorization: Bearer header.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !s.validToken(token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="instadiff"`)
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) validToken(token string) bool {
+	var valid bool
+
+	// all tokens are compared to not leak which one matched by timing.
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+			valid = true
+		}
+	}
+
+	return valid
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(sw, r)
+
+		log.WithFields(r.Context(), log.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   sw.status,
+			"duration": time.Since(start).String(),
+		}).Debug("API request")
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// handleUsers serves the list fetched by the last job of the action, instagram is not called on request.
+func (s *Server) handleUsers(name, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := parsePage(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		list, ok := s.lists.get(name)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("%s are not fetched yet, run %s action", name, action))
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, usersPageResp{
+			pageResp:  makePage(p, s.makeUsers(list.users)),
+			UpdatedAt: list.updatedAt,
+		})
+	}
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	var f func(ctx context.Context) ([]models.UsersBatch, error)
+
+	switch r.PathValue("type") {
+	case "followers":
+		f = s.svc.GetDiffFollowers
+	case "followings":
+		f = s.svc.GetDiffFollowings
+	case "blocked":
+		f = s.svc.GetDiffBlocked
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown diff type (%s)", r.PathValue("type")))
+
+		return
+	}
+
+	batches, err := f(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.makeBatches(batches))
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	var f func(ctx context.Context) (models.DiffHistory, error)
+
+	switch r.PathValue("type") {
+	case "followers":
+		f = s.svc.GetHistoryDiffFollowers
+	case "followings":
+		f = s.svc.GetHistoryDiffFollowings
+	case "blocked":
+		f = s.svc.GetHistoryDiffBlocked
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown history type (%s)", r.PathValue("type")))
+
+		return
+	}
+
+	p, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	history, err := f(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, makePage(p, s.makeHistory(history)))
+}
+
+// actionRequest is a body of the action request.
+type actionRequest struct {
+	Usernames []string `json:"usernames"`
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request) {
+	action := r.PathValue("action")
+
+	var req actionRequest
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("decode body: %w", err))
+
+			return
+		}
+	}
+
+	fn, err := s.actionFunc(action, req.Usernames)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	jb, err := s.jobs.add(action, req.Usernames, fn)
+	if err != nil {
+		if errors.Is(err, ErrJobsQueueFull) {
+			writeError(w, http.StatusServiceUnavailable, err)
+
+			return
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/jobs/"+jb.ID)
+	writeJSON(w, http.StatusAccepted, makeJob(jb))
+}
+
+// actionFunc returns job func of the action, actions by username require usernames.
+func (s *Server) actionFunc(action string, usernames []string) (jobFunc, error) {
+	byUsernames := map[string]func(ctx context.Context, usernames []string) (int, error){
+		"follow":           s.svc.FollowUsers,
+		"unfollow":         s.svc.UnfollowUsers,
+		"remove-followers": s.svc.RemoveFollowersByUsername,
+		"block":            s.svc.BlockUsers,
+		"unblock":          s.svc.UnblockUsers,
+		"whitelist":        s.svc.WhitelistUsers,
+	}
+
+	withoutUsernames := map[string]jobFunc{
+		"unfollow-not-mutual": s.svc.UnFollowAllNotMutualExceptWhitelisted,
+		actionRefresh:         s.refresh,
+		actionUseless:         s.findUseless,
+	}
+
+	if f, ok := withoutUsernames[action]; ok {
+		return f, nil
+	}
+
+	f, ok := byUsernames[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown action (%s)", action)
+	}
+
+	if len(usernames) == 0 {
+		return nil, errors.New("usernames are not passed")
+	}
+
+	return func(ctx context.Context) (int, error) {
+		return f(ctx, usernames)
+	}, nil
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	list := s.jobs.all()
+
+	resp := make([]jobResp, 0, len(list))
+
+	for _, jb := range list {
+		resp = append(resp, makeJob(jb))
+	}
+
+	writeJSON(w, http.StatusOK, makePage(p, resp))
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	jb, err := s.jobs.get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, makeJob(jb))
+}
+
+// page is a requested part of the list.
+type page struct {
+	limit  int
+	offset int
+}
+
+// parsePage reads limit and offset query parameters.
+func parsePage(r *http.Request) (page, error) {
+	p := page{
+		limit:  defaultLimit,
+		offset: 0,
+	}
+
+	q := r.URL.Query()
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			return page{}, fmt.Errorf("limit should be from 1 to %d", maxLimit)
+		}
+
+		p.limit = n
+	}
+
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page{}, errors.New("offset should be positive number")
+		}
+
+		p.offset = n
+	}
+
+	return p, nil
+}
+
+// pageResp is a paginated list response.
+type pageResp[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// usersPageResp is a paginated users list with the time it was fetched.
+type usersPageResp struct {
+	pageResp[userResp]
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func makePage[T any](p page, items []T) pageResp[T] {
+	start := min(p.offset, len(items))
+	end := min(start+p.limit, len(items))
+
+	return pageResp[T]{
+		Items:  items[start:end],
+		Total:  len(items),
+		Limit:  p.limit,
+		Offset: p.offset,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	// headers are already sent, so encoding error could not be reported to the client.
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type errorResp struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResp{Error: err.Error()})
+}
@@ -0,0 +1,579 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+const testToken = "secret"
+
+type fakeService struct {
+	mu         sync.Mutex
+	users      []models.User
+	followings []models.User
+	batches    []models.UsersBatch
+	history    models.DiffHistory
+	err        error
+	unfollowed []string
+}
+
+func (f *fakeService) listUsers(context.Context) ([]models.User, error) {
+	return f.users, f.err
+}
+
+func (f *fakeService) listBatches(context.Context) ([]models.UsersBatch, error) {
+	return f.batches, f.err
+}
+
+func (f *fakeService) listHistory(context.Context) (models.DiffHistory, error) {
+	return f.history, f.err
+}
+
+func (f *fakeService) byUsernames(_ context.Context, usernames []string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.unfollowed = append(f.unfollowed, usernames...)
+
+	return len(usernames), f.err
+}
+
+func (f *fakeService) GetFollowers(ctx context.Context) ([]models.User, error) {
+	return f.listUsers(ctx)
+}
+
+func (f *fakeService) GetFollowings(_ context.Context) ([]models.User, error) {
+	return f.followings, f.err
+}
+
+func (f *fakeService) GetUselessFollowers(ctx context.Context) ([]models.User, error) {
+	return f.listUsers(ctx)
+}
+
+func (f *fakeService) GetDiffFollowers(ctx context.Context) ([]models.UsersBatch, error) {
+	return f.listBatches(ctx)
+}
+
+func (f *fakeService) GetDiffFollowings(ctx context.Context) ([]models.UsersBatch, error) {
+	return f.listBatches(ctx)
+}
+
+func (f *fakeService) GetDiffBlocked(ctx context.Context) ([]models.UsersBatch, error) {
+	return f.listBatches(ctx)
+}
+
+func (f *fakeService) GetHistoryDiffFollowers(ctx context.Context) (models.DiffHistory, error) {
+	return f.listHistory(ctx)
+}
+
+func (f *fakeService) GetHistoryDiffFollowings(ctx context.Context) (models.DiffHistory, error) {
+	return f.listHistory(ctx)
+}
+
+func (f *fakeService) GetHistoryDiffBlocked(ctx context.Context) (models.DiffHistory, error) {
+	return f.listHistory(ctx)
+}
+
+func (f *fakeService) FollowUsers(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) UnfollowUsers(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) UnFollowAllNotMutualExceptWhitelisted(ctx context.Context) (int, error) {
+	return f.byUsernames(ctx, []string{"not_mutual"})
+}
+
+func (f *fakeService) RemoveFollowersByUsername(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) BlockUsers(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) UnblockUsers(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) WhitelistUsers(ctx context.Context, usernames []string) (int, error) {
+	return f.byUsernames(ctx, usernames)
+}
+
+func (f *fakeService) IsWhitelisted(u models.User) bool {
+	return u.UserName == "user1"
+}
+
+func makeTestUsers(n int) []models.User {
+	users := make([]models.User, 0, n)
+
+	for i := 0; i < n; i++ {
+		users = append(users, models.MakeUser(int64(i+1), fmt.Sprintf("user%d", i+1), fmt.Sprintf("User %d", i+1)))
+	}
+
+	return users
+}
+
+func newTestServer(t *testing.T, svc *fakeService) *Server {
+	t.Helper()
+
+	srv, err := New(svc, Params{
+		Addr:   "127.0.0.1:0",
+		Tokens: []string{"", testToken},
+	})
+	require.NoError(t, err)
+
+	return srv
+}
+
+func doRequest(t *testing.T, h http.Handler, method, target, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Params
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			p:       Params{Addr: ":8080", Tokens: []string{"token"}},
+			wantErr: nil,
+		},
+		{
+			name:    "empty address",
+			p:       Params{Addr: "", Tokens: []string{"token"}},
+			wantErr: ErrEmptyAddr,
+		},
+		{
+			name:    "blank tokens",
+			p:       Params{Addr: ":8080", Tokens: []string{"", "  "}},
+			wantErr: ErrNoTokens,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&fakeService{}, tt.p)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestServer_Handler_Auth(t *testing.T) {
+	h := newTestServer(t, &fakeService{}).Handler()
+
+	tests := []struct {
+		name   string
+		target string
+		token  string
+		want   int
+	}{
+		{
+			name:   "health without token",
+			target: "/api/v1/health",
+			token:  "",
+			want:   http.StatusOK,
+		},
+		{
+			name:   "missing token",
+			target: "/api/v1/jobs",
+			token:  "",
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong token",
+			target: "/api/v1/jobs",
+			token:  "wrong",
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "valid token",
+			target: "/api/v1/jobs",
+			token:  testToken,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "unknown route",
+			target: "/api/v1/unknown",
+			token:  testToken,
+			want:   http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, h, http.MethodGet, tt.target, tt.token, "")
+			assert.Equal(t, tt.want, w.Code)
+
+			if tt.want == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestServer_Handler_Users(t *testing.T) {
+	srv := newTestServer(t, &fakeService{})
+	h := srv.Handler()
+
+	srv.lists.set(listNotMutual, makeTestUsers(5), time.Now())
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantNames  []string
+		wantTotal  int
+	}{
+		{
+			name:       "default page",
+			query:      "",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"user1", "user2", "user3", "user4", "user5"},
+			wantTotal:  5,
+		},
+		{
+			name:       "limit and offset",
+			query:      "?limit=2&offset=1",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"user2", "user3"},
+			wantTotal:  5,
+		},
+		{
+			name:       "offset out of range",
+			query:      "?offset=10",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{},
+			wantTotal:  5,
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid offset",
+			query:      "?offset=-1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, h, http.MethodGet, "/api/v1/not-mutual"+tt.query, testToken, "")
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got usersPageResp
+
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+			assert.False(t, got.UpdatedAt.IsZero())
+
+			names := make([]string, 0, len(got.Items))
+
+			for _, u := range got.Items {
+				names = append(names, u.Username)
+			}
+
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantTotal, got.Total)
+
+			for _, u := range got.Items {
+				assert.Equal(t, u.Username == "user1", u.Whitelisted, u.Username)
+			}
+		})
+	}
+}
+
+func TestServer_Handler_FetchLists(t *testing.T) {
+	svc := &fakeService{
+		users:      makeTestUsers(3),
+		followings: makeTestUsers(5),
+	}
+	srv := newTestServer(t, svc)
+	h := srv.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go srv.jobs.run(ctx)
+
+	for _, target := range []string{"/api/v1/followers", "/api/v1/not-mutual", "/api/v1/useless"} {
+		w := doRequest(t, h, http.MethodGet, target, testToken, "")
+		assert.Equal(t, http.StatusNotFound, w.Code, target)
+	}
+
+	// lists are fetched by jobs and served without service calls afterwards.
+	for action, want := range map[string]int{"refresh": 8, "useless": 3} {
+		got := runJob(t, h, action)
+		assert.Equal(t, jobStatusDone.String(), got.Status, action)
+		assert.Equal(t, want, got.Processed, action)
+	}
+
+	svc.mu.Lock()
+	svc.err = errors.New("instagram is down")
+	svc.mu.Unlock()
+
+	for target, want := range map[string][]string{
+		"/api/v1/followers":  {"user1", "user2", "user3"},
+		"/api/v1/followings": {"user1", "user2", "user3", "user4", "user5"},
+		"/api/v1/not-mutual": {"user4", "user5"},
+		"/api/v1/useless":    {"user1", "user2", "user3"},
+	} {
+		w := doRequest(t, h, http.MethodGet, target, testToken, "")
+		require.Equal(t, http.StatusOK, w.Code, target)
+
+		var got usersPageResp
+
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+
+		names := make([]string, 0, len(got.Items))
+
+		for _, u := range got.Items {
+			names = append(names, u.Username)
+		}
+
+		assert.Equal(t, want, names, target)
+	}
+
+	got := runJob(t, h, "refresh")
+	assert.Equal(t, jobStatusFailed.String(), got.Status)
+	assert.Equal(t, "get followers: instagram is down", got.Error)
+}
+
+// runJob schedules action without usernames and waits for the job to finish.
+func runJob(t *testing.T, h http.Handler, action string) jobResp {
+	t.Helper()
+
+	w := doRequest(t, h, http.MethodPost, "/api/v1/actions/"+action, testToken, "")
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var created jobResp
+
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	var got jobResp
+
+	require.Eventually(t, func() bool {
+		w := doRequest(t, h, http.MethodGet, "/api/v1/jobs/"+created.ID, testToken, "")
+
+		got = jobResp{}
+
+		return json.NewDecoder(w.Body).Decode(&got) == nil &&
+			(got.Status == jobStatusDone.String() || got.Status == jobStatusFailed.String())
+	}, time.Second, 10*time.Millisecond)
+
+	return got
+}
+
+func TestServer_Handler_Diff(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	history := models.MakeDiffHistory(models.DiffTypeFollowers)
+	history.Add(
+		models.MakeUsersBatch(models.UsersBatchTypeNewFollowers, makeTestUsers(2), now.Add(-time.Hour)),
+		models.MakeUsersBatch(models.UsersBatchTypeLostFollowers, makeTestUsers(1), now),
+	)
+
+	h := newTestServer(t, &fakeService{
+		batches: []models.UsersBatch{
+			models.MakeUsersBatch(models.UsersBatchTypeLostFollowers, makeTestUsers(1), now),
+		},
+		history: history,
+	}).Handler()
+
+	t.Run("diff", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodGet, "/api/v1/diff/followers", testToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var got []batchResp
+
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		require.Len(t, got, 1)
+		assert.Equal(t, models.UsersBatchTypeLostFollowers.String(), got[0].Type)
+		assert.Equal(t, 1, got[0].Count)
+	})
+
+	t.Run("history latest first", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodGet, "/api/v1/history/followers", testToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var got pageResp[historyResp]
+
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		require.Len(t, got.Items, 2)
+		assert.True(t, got.Items[0].Date.Equal(now))
+		assert.Equal(t, 2, got.Items[1].Batches[0].Count)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodGet, "/api/v1/diff/likes", testToken, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServer_Handler_Actions(t *testing.T) {
+	svc := &fakeService{}
+	srv := newTestServer(t, svc)
+	h := srv.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go srv.jobs.run(ctx)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for target, body := range map[string]string{
+			"/api/v1/actions/like":     `{"usernames": ["user1"]}`,
+			"/api/v1/actions/unfollow": `{"usernames": []}`,
+			"/api/v1/actions/block":    `{"usernames":`,
+		} {
+			w := doRequest(t, h, http.MethodPost, target, testToken, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		}
+	})
+
+	t.Run("job status", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodPost, "/api/v1/actions/unfollow", testToken, `{"usernames": ["user1", "user2"]}`)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		var created jobResp
+
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+		assert.Equal(t, "/api/v1/jobs/"+created.ID, w.Header().Get("Location"))
+		assert.Equal(t, "unfollow", created.Action)
+
+		var got jobResp
+
+		require.Eventually(t, func() bool {
+			w := doRequest(t, h, http.MethodGet, "/api/v1/jobs/"+created.ID, testToken, "")
+			if w.Code != http.StatusOK {
+				return false
+			}
+
+			got = jobResp{}
+
+			return json.NewDecoder(w.Body).Decode(&got) == nil && got.Status == jobStatusDone.String()
+		}, time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, 2, got.Processed)
+		assert.NotNil(t, got.FinishedAt)
+
+		svc.mu.Lock()
+		assert.Equal(t, []string{"user1", "user2"}, svc.unfollowed)
+		svc.mu.Unlock()
+
+		w = doRequest(t, h, http.MethodGet, "/api/v1/jobs", testToken, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var list pageResp[jobResp]
+
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+		assert.Equal(t, 1, list.Total)
+	})
+
+	t.Run("whitelist", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodPost, "/api/v1/actions/whitelist", testToken, `{"usernames": ["user3"]}`)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		require.Eventually(t, func() bool {
+			svc.mu.Lock()
+			defer svc.mu.Unlock()
+
+			return len(svc.unfollowed) == 3 && svc.unfollowed[2] == "user3"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		w := doRequest(t, h, http.MethodGet, "/api/v1/jobs/unknown", testToken, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServer_Handler_Dashboard(t *testing.T) {
+	h := newTestServer(t, &fakeService{}).Handler()
+
+	tests := []struct {
+		name            string
+		target          string
+		wantStatus      int
+		wantContentType string
+	}{
+		{
+			name:            "index",
+			target:          "/",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+		},
+		{
+			name:            "script",
+			target:          "/app.js",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/javascript; charset=utf-8",
+		},
+		{
+			name:            "style",
+			target:          "/style.css",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/css; charset=utf-8",
+		},
+		{
+			name:       "missing asset",
+			target:     "/missing.js",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			// assets are public, token is entered in the dashboard.
+			w := doRequest(t, h, http.MethodGet, tt.target, "", "")
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			assert.NotEmpty(t, w.Header().Get("Content-Security-Policy"))
+
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
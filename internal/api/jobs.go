@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/obalunenko/logger"
+)
+
+//go:generate stringer -type=jobStatus -trimprefix=jobStatus -linecomment
+
+// jobStatus represents state of the async job.
+type jobStatus uint
+
+const (
+	// jobStatusUnknown is unknown status, to cover default value case.
+	jobStatusUnknown jobStatus = iota // unknown
+
+	// jobStatusPending means that job waits for the previous jobs to finish.
+	jobStatusPending // pending
+	// jobStatusRunning means that job is running.
+	jobStatusRunning // running
+	// jobStatusDone means that job finished successfully.
+	jobStatusDone // done
+	// jobStatusFailed means that job finished with error.
+	jobStatusFailed // failed
+)
+
+const (
+	// maxPendingJobs limits number of jobs waiting to run.
+	maxPendingJobs = 100
+	// maxFinishedJobs is a number of the latest finished jobs kept for status requests.
+	maxFinishedJobs = 100
+)
+
+// jobFunc runs account action and returns number of processed users.
+type jobFunc func(ctx context.Context) (int, error)
+
+// job is a long-running account action executed in background.
+type job struct {
+	ID         string
+	Action     string
+	Usernames  []string
+	Status     jobStatus
+	Processed  int
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	fn jobFunc
+}
+
+// jobs runs jobs one by one, so account actions are not sent concurrently and are paced by the client.
+type jobs struct {
+	mu sync.Mutex
+	// list is ordered by creation time.
+	list  []*job
+	queue chan *job
+}
+
+func newJobs() *jobs {
+	return &jobs{
+		list:  nil,
+		queue: make(chan *job, maxPendingJobs),
+	}
+}
+
+// add schedules job and returns its snapshot.
+func (j *jobs) add(action string, usernames []string, fn jobFunc) (job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return job{}, err
+	}
+
+	jb := &job{
+		ID:        id,
+		Action:    action,
+		Usernames: usernames,
+		Status:    jobStatusPending,
+		CreatedAt: time.Now(),
+		fn:        fn,
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	select {
+	case j.queue <- jb:
+	default:
+		return job{}, ErrJobsQueueFull
+	}
+
+	j.list = append(j.list, jb)
+	j.prune()
+
+	return *jb, nil
+}
+
+// get returns snapshot of the job by id.
+func (j *jobs) get(id string) (job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, jb := range j.list {
+		if jb.ID == id {
+			return *jb, nil
+		}
+	}
+
+	return job{}, fmt.Errorf("[%s]: %w", id, ErrJobNotFound)
+}
+
+// all returns snapshots of the jobs, the latest first.
+func (j *jobs) all() []job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	list := make([]job, 0, len(j.list))
+
+	for i := len(j.list) - 1; i >= 0; i-- {
+		list = append(list, *j.list[i])
+	}
+
+	return list
+}
+
+// run executes scheduled jobs until context is canceled.
+func (j *jobs) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jb := <-j.queue:
+			j.exec(ctx, jb)
+		}
+	}
+}
+
+func (j *jobs) exec(ctx context.Context, jb *job) {
+	j.update(jb, func(jb *job) {
+		jb.Status = jobStatusRunning
+		jb.StartedAt = time.Now()
+	})
+
+	n, err := jb.fn(ctx)
+
+	j.update(jb, func(jb *job) {
+		jb.Processed = n
+		jb.FinishedAt = time.Now()
+		jb.Status = jobStatusDone
+
+		if err != nil {
+			jb.Status = jobStatusFailed
+			jb.Error = err.Error()
+		}
+	})
+
+	l := log.WithFields(ctx, log.Fields{
+		"id":        jb.ID,
+		"action":    jb.Action,
+		"processed": n,
+	})
+
+	if err != nil {
+		l.WithError(err).Warn("Job failed")
+
+		return
+	}
+
+	l.Info("Job done")
+}
+
+func (j *jobs) update(jb *job, f func(jb *job)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f(jb)
+}
+
+// prune drops the oldest finished jobs above the limit, pending and running ones are kept.
+func (j *jobs) prune() {
+	var finished int
+
+	for _, jb := range j.list {
+		if jb.Status == jobStatusDone || jb.Status == jobStatusFailed {
+			finished++
+		}
+	}
+
+	if finished <= maxFinishedJobs {
+		return
+	}
+
+	drop := finished - maxFinishedJobs
+
+	list := j.list[:0]
+
+	for _, jb := range j.list {
+		if drop > 0 && (jb.Status == jobStatusDone || jb.Status == jobStatusFailed) {
+			drop--
+
+			continue
+		}
+
+		list = append(list, jb)
+	}
+
+	j.list = list
+}
+
+func newJobID() (string, error) {
+	const size = 8
+
+	b := make([]byte, size)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
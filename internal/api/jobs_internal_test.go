@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobs_exec(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         jobFunc
+		wantStatus jobStatus
+		wantError  string
+		wantN      int
+	}{
+		{
+			name: "done",
+			fn: func(context.Context) (int, error) {
+				return 3, nil
+			},
+			wantStatus: jobStatusDone,
+			wantError:  "",
+			wantN:      3,
+		},
+		{
+			name: "failed",
+			fn: func(context.Context) (int, error) {
+				return 1, errors.New("rate limited")
+			},
+			wantStatus: jobStatusFailed,
+			wantError:  "rate limited",
+			wantN:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			j := newJobs()
+
+			created, err := j.add("unfollow", []string{"user1"}, tt.fn)
+			require.NoError(t, err)
+			assert.Equal(t, jobStatusPending, created.Status)
+
+			j.exec(context.Background(), <-j.queue)
+
+			got, err := j.get(created.ID)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantError, got.Error)
+			assert.Equal(t, tt.wantN, got.Processed)
+			assert.False(t, got.StartedAt.IsZero())
+			assert.False(t, got.FinishedAt.IsZero())
+		})
+	}
+}
+
+func TestJobs_add_QueueFull(t *testing.T) {
+	j := newJobs()
+
+	fn := func(context.Context) (int, error) {
+		return 0, nil
+	}
+
+	for i := 0; i < maxPendingJobs; i++ {
+		_, err := j.add("follow", nil, fn)
+		require.NoError(t, err)
+	}
+
+	_, err := j.add("follow", nil, fn)
+	require.ErrorIs(t, err, ErrJobsQueueFull)
+
+	assert.Len(t, j.all(), maxPendingJobs)
+}
+
+func TestJobs_prune(t *testing.T) {
+	j := newJobs()
+
+	fn := func(context.Context) (int, error) {
+		return 0, nil
+	}
+
+	var first job
+
+	for i := 0; i < maxFinishedJobs+1; i++ {
+		jb, err := j.add("follow", nil, fn)
+		require.NoError(t, err)
+
+		if i == 0 {
+			first = jb
+		}
+
+		j.exec(context.Background(), <-j.queue)
+	}
+
+	pending, err := j.add("follow", nil, fn)
+	require.NoError(t, err)
+
+	list := j.all()
+	require.Len(t, list, maxFinishedJobs+1)
+	assert.Equal(t, pending.ID, list[0].ID)
+
+	_, err = j.get(first.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
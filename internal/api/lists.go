@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/internal/service"
+)
+
+// Users lists served by the API, they are fetched by jobs of the actions.
+const (
+	listFollowers  = "followers"
+	listFollowings = "followings"
+	listNotMutual  = "not-mutual"
+	listUseless    = "useless"
+)
+
+// Actions that fetch users lists.
+const (
+	actionRefresh = "refresh"
+	actionUseless = "useless"
+)
+
+// userList is a users list fetched by the job.
+type userList struct {
+	users     []models.User
+	updatedAt time.Time
+}
+
+// userLists keeps the last fetched users lists, so list requests are paginated without calls to instagram.
+type userLists struct {
+	mu    sync.RWMutex
+	lists map[string]userList
+}
+
+func newUserLists() *userLists {
+	return &userLists{
+		mu:    sync.RWMutex{},
+		lists: make(map[string]userList),
+	}
+}
+
+func (l *userLists) set(name string, users []models.User, updatedAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lists[name] = userList{
+		users:     users,
+		updatedAt: updatedAt,
+	}
+}
+
+func (l *userLists) get(name string) (userList, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	list, ok := l.lists[name]
+
+	return list, ok
+}
+
+// refresh fetches followers and followings once and keeps them with not mutual followers.
+// Returns number of fetched users.
+func (s *Server) refresh(ctx context.Context) (int, error) {
+	followers, err := s.svc.GetFollowers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get followers: %w", err)
+	}
+
+	followings, err := s.svc.GetFollowings(ctx)
+	if err != nil {
+		return len(followers), fmt.Errorf("get followings: %w", err)
+	}
+
+	now := time.Now()
+
+	s.lists.set(listFollowers, followers, now)
+	s.lists.set(listFollowings, followings, now)
+	s.lists.set(listNotMutual, service.NotMutual(followers, followings), now)
+
+	return len(followers) + len(followings), nil
+}
+
+// findUseless checks all followers and keeps useless ones. Returns number of useless followers.
+func (s *Server) findUseless(ctx context.Context) (int, error) {
+	users, err := s.svc.GetUselessFollowers(ctx)
+	if err != nil && !errors.Is(err, service.ErrNoUsers) {
+		return 0, err
+	}
+
+	s.lists.set(listUseless, users, time.Now())
+
+	return len(users), nil
+}
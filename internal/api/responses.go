@@ -0,0 +1,115 @@
+package api
+
+import (
+	"slices"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+type userResp struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	FullName    string `json:"full_name"`
+	Whitelisted bool   `json:"whitelisted"`
+}
+
+func (s *Server) makeUsers(users []models.User) []userResp {
+	resp := make([]userResp, 0, len(users))
+
+	for _, u := range users {
+		resp = append(resp, userResp{
+			ID:          u.ID,
+			Username:    u.UserName,
+			FullName:    u.FullName,
+			Whitelisted: s.svc.IsWhitelisted(u),
+		})
+	}
+
+	return resp
+}
+
+type batchResp struct {
+	Type      string     `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+	Count     int        `json:"count"`
+	Users     []userResp `json:"users"`
+}
+
+func (s *Server) makeBatches(batches []models.UsersBatch) []batchResp {
+	resp := make([]batchResp, 0, len(batches))
+
+	for _, b := range batches {
+		resp = append(resp, batchResp{
+			Type:      b.Type.String(),
+			CreatedAt: b.CreatedAt,
+			Count:     len(b.Users),
+			Users:     s.makeUsers(b.Users),
+		})
+	}
+
+	return resp
+}
+
+type historyResp struct {
+	Date    time.Time   `json:"date"`
+	Batches []batchResp `json:"batches"`
+}
+
+// makeHistory returns history entries, the latest first.
+func (s *Server) makeHistory(h models.DiffHistory) []historyResp {
+	dates := make([]time.Time, 0, len(h.History))
+
+	for d := range h.History {
+		dates = append(dates, d)
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int {
+		return b.Compare(a)
+	})
+
+	resp := make([]historyResp, 0, len(dates))
+
+	for _, d := range dates {
+		resp = append(resp, historyResp{
+			Date:    d,
+			Batches: s.makeBatches(h.Get(d)),
+		})
+	}
+
+	return resp
+}
+
+type jobResp struct {
+	ID         string     `json:"id"`
+	Action     string     `json:"action"`
+	Usernames  []string   `json:"usernames,omitempty"`
+	Status     string     `json:"status"`
+	Processed  int        `json:"processed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func makeJob(jb job) jobResp {
+	return jobResp{
+		ID:         jb.ID,
+		Action:     jb.Action,
+		Usernames:  jb.Usernames,
+		Status:     jb.Status.String(),
+		Processed:  jb.Processed,
+		Error:      jb.Error,
+		CreatedAt:  jb.CreatedAt,
+		StartedAt:  optionalTime(jb.StartedAt),
+		FinishedAt: optionalTime(jb.FinishedAt),
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,390 @@
+'use strict';
+
+// Dashboard talks to the JSON API of the same server, token is kept in the browser local storage.
+const tokenKey = 'instadiff.token';
+const pageSize = 50;
+const historyPageSize = 1000;
+const jobPollInterval = 2000;
+
+const svgNS = 'http://www.w3.org/2000/svg';
+
+const state = {
+  notMutualOffset: 0,
+  notMutualTotal: 0,
+  selectedPoint: null,
+  pollTimer: null,
+};
+
+function $(id) {
+  return document.getElementById(id);
+}
+
+function showMessage(text, info) {
+  const el = $('message');
+
+  el.textContent = text;
+  el.classList.toggle('info', Boolean(info));
+  el.hidden = false;
+}
+
+function hideMessage() {
+  $('message').hidden = true;
+}
+
+async function api(path, options) {
+  const opts = options || {};
+  const headers = Object.assign({}, opts.headers, {
+    Authorization: 'Bearer ' + (localStorage.getItem(tokenKey) || ''),
+  });
+
+  const resp = await fetch(path, Object.assign({}, opts, {headers: headers}));
+  const body = await resp.json().catch(() => ({}));
+
+  if (!resp.ok) {
+    throw new Error(body.error || resp.status + ' ' + resp.statusText);
+  }
+
+  return body;
+}
+
+// fetchAll reads all pages of the paginated list.
+async function fetchAll(path, limit) {
+  const items = [];
+
+  for (let offset = 0; ; offset += limit) {
+    const page = await api(path + '?limit=' + limit + '&offset=' + offset);
+
+    items.push(...page.items);
+
+    if (items.length >= page.total || page.items.length === 0) {
+      return items;
+    }
+  }
+}
+
+function withErrors(f) {
+  return async function (...args) {
+    hideMessage();
+
+    try {
+      await f(...args);
+    } catch (err) {
+      showMessage(err.message);
+    }
+  };
+}
+
+function formatDate(value) {
+  return new Date(value).toLocaleString();
+}
+
+function element(tag, text, className) {
+  const el = document.createElement(tag);
+
+  if (text !== undefined) {
+    el.textContent = text;
+  }
+
+  if (className) {
+    el.className = className;
+  }
+
+  return el;
+}
+
+function profileLink(username) {
+  const a = element('a', username);
+
+  a.href = 'https://www.instagram.com/' + encodeURIComponent(username) + '/';
+  a.target = '_blank';
+  a.rel = 'noopener noreferrer';
+
+  return a;
+}
+
+// growth turns diff history into net change points, the oldest first.
+function growth(history) {
+  const points = [];
+  let total = 0;
+
+  for (const entry of history.slice().reverse()) {
+    const point = {date: entry.date, added: [], lost: [], total: 0};
+
+    for (const batch of entry.batches) {
+      if (batch.type.startsWith('New')) {
+        point.added.push(...batch.users);
+      } else if (batch.type.startsWith('Lost')) {
+        point.lost.push(...batch.users);
+      }
+    }
+
+    total += point.added.length - point.lost.length;
+    point.total = total;
+
+    points.push(point);
+  }
+
+  return points;
+}
+
+function svgElement(tag, attrs, text) {
+  const el = document.createElementNS(svgNS, tag);
+
+  for (const [k, v] of Object.entries(attrs)) {
+    el.setAttribute(k, v);
+  }
+
+  if (text !== undefined) {
+    el.textContent = text;
+  }
+
+  return el;
+}
+
+function drawChart(svg, title, points) {
+  const width = 600;
+  const height = 240;
+  const pad = {top: 16, right: 16, bottom: 32, left: 48};
+
+  svg.replaceChildren();
+
+  if (points.length === 0) {
+    svg.append(svgElement('text', {x: pad.left, y: height / 2, class: 'label'}, 'No stored history'));
+
+    return;
+  }
+
+  const times = points.map((p) => new Date(p.date).getTime());
+  const totals = points.map((p) => p.total);
+
+  const minT = Math.min(...times);
+  const maxT = Math.max(...times);
+  const minV = Math.min(0, ...totals);
+  const maxV = Math.max(0, ...totals);
+
+  const x = (t) => pad.left + (maxT === minT ? 0.5 : (t - minT) / (maxT - minT)) * (width - pad.left - pad.right);
+  const y = (v) => height - pad.bottom - (maxV === minV ? 0.5 : (v - minV) / (maxV - minV)) * (height - pad.top - pad.bottom);
+
+  svg.append(
+    svgElement('line', {x1: pad.left, y1: y(0), x2: width - pad.right, y2: y(0), class: 'axis'}),
+    svgElement('line', {x1: pad.left, y1: pad.top, x2: pad.left, y2: height - pad.bottom, class: 'axis'}),
+    svgElement('text', {x: 4, y: y(maxV) + 4, class: 'label'}, String(maxV)),
+    svgElement('text', {x: 4, y: y(minV) + 4, class: 'label'}, String(minV)),
+    svgElement('text', {x: pad.left, y: height - 8, class: 'label'}, new Date(minT).toLocaleDateString()),
+    svgElement('text', {x: width - pad.right, y: height - 8, class: 'label', 'text-anchor': 'end'},
+      new Date(maxT).toLocaleDateString()),
+  );
+
+  const coords = points.map((p, i) => x(times[i]) + ',' + y(p.total)).join(' ');
+
+  svg.append(svgElement('polyline', {points: coords, class: 'line'}));
+
+  points.forEach((p, i) => {
+    const circle = svgElement('circle', {cx: x(times[i]), cy: y(p.total), r: 5, class: 'point', tabindex: 0});
+
+    circle.append(svgElement('title', {}, formatDate(p.date) + ': +' + p.added.length + ' / -' + p.lost.length));
+
+    const select = () => {
+      if (state.selectedPoint) {
+        state.selectedPoint.classList.remove('selected');
+      }
+
+      circle.classList.add('selected');
+      state.selectedPoint = circle;
+
+      showDrilldown(title, p);
+    };
+
+    circle.addEventListener('click', select);
+    circle.addEventListener('keydown', (e) => {
+      if (e.key === 'Enter') {
+        select();
+      }
+    });
+
+    svg.append(circle);
+  });
+}
+
+function fillUsers(list, users) {
+  list.replaceChildren();
+
+  for (const u of users) {
+    const li = element('li');
+
+    li.append(profileLink(u.username));
+
+    if (u.full_name) {
+      li.append(' ' + u.full_name);
+    }
+
+    list.append(li);
+  }
+}
+
+function showDrilldown(title, point) {
+  $('drilldown-title').textContent = title + ' on ' + formatDate(point.date);
+  $('drilldown-new-count').textContent = '(' + point.added.length + ')';
+  $('drilldown-lost-count').textContent = '(' + point.lost.length + ')';
+
+  fillUsers($('drilldown-new'), point.added);
+  fillUsers($('drilldown-lost'), point.lost);
+
+  $('drilldown').hidden = false;
+}
+
+async function loadGrowth() {
+  const [followers, followings] = await Promise.all([
+    fetchAll('/api/v1/history/followers', historyPageSize),
+    fetchAll('/api/v1/history/followings', historyPageSize),
+  ]);
+
+  drawChart($('chart-followers'), 'Followers', growth(followers));
+  drawChart($('chart-followings'), 'Followings', growth(followings));
+}
+
+async function loadNotMutual() {
+  const page = await api('/api/v1/not-mutual?limit=' + pageSize + '&offset=' + state.notMutualOffset);
+
+  state.notMutualTotal = page.total;
+
+  const body = $('not-mutual');
+
+  body.replaceChildren();
+
+  for (const u of page.items) {
+    const row = element('tr');
+    const name = element('td');
+    const actions = element('td');
+
+    name.append(profileLink(u.username));
+
+    const whitelist = element('button', 'Whitelist');
+
+    whitelist.type = 'button';
+    whitelist.disabled = u.whitelisted;
+    whitelist.addEventListener('click', withErrors(() => runAction('whitelist', [u.username], whitelist)));
+
+    const unfollow = element('button', 'Unfollow');
+
+    unfollow.type = 'button';
+    unfollow.addEventListener('click', withErrors(() => runAction('unfollow', [u.username], unfollow)));
+
+    actions.append(whitelist, unfollow);
+
+    row.append(name, element('td', u.full_name), element('td', u.whitelisted ? 'yes' : 'no'), actions);
+    body.append(row);
+  }
+
+  const from = page.total === 0 ? 0 : page.offset + 1;
+
+  $('not-mutual-page').textContent = from + '-' + (page.offset + page.items.length) + ' of ' + page.total +
+    ', fetched ' + formatDate(page.updated_at);
+  $('not-mutual-prev').disabled = page.offset === 0;
+  $('not-mutual-next').disabled = page.offset + page.items.length >= page.total;
+}
+
+async function runAction(action, usernames, button) {
+  if (action === 'unfollow' && !confirm('Unfollow ' + usernames.join(', ') + '?')) {
+    return;
+  }
+
+  button.disabled = true;
+
+  const job = await api('/api/v1/actions/' + action, {
+    method: 'POST',
+    headers: {'Content-Type': 'application/json'},
+    body: JSON.stringify({usernames: usernames}),
+  });
+
+  showMessage('Job ' + job.id + ' (' + job.action + ') scheduled', true);
+
+  await loadJobs();
+}
+
+// fetchLists schedules job that fetches followers and followings, lists are loaded when it is done.
+async function fetchLists(button) {
+  button.disabled = true;
+
+  try {
+    const job = await api('/api/v1/actions/refresh', {method: 'POST'});
+
+    showMessage('Job ' + job.id + ' (' + job.action + ') scheduled, load the list when it is done', true);
+
+    await loadJobs();
+  } finally {
+    button.disabled = false;
+  }
+}
+
+function renderJobs(jobs) {
+  const body = $('jobs');
+
+  body.replaceChildren();
+
+  for (const j of jobs) {
+    const row = element('tr');
+
+    row.append(
+      element('td', formatDate(j.created_at)),
+      element('td', j.action),
+      element('td', (j.usernames || []).join(', ')),
+      element('td', j.status, 'status-' + j.status),
+      element('td', String(j.processed)),
+      element('td', j.error || ''),
+    );
+
+    body.append(row);
+  }
+}
+
+// loadJobs refreshes jobs and keeps polling while some of them are not finished.
+async function loadJobs() {
+  clearTimeout(state.pollTimer);
+
+  const page = await api('/api/v1/jobs?limit=' + pageSize);
+
+  renderJobs(page.items);
+
+  const active = page.items.some((j) => j.status === 'pending' || j.status === 'running');
+
+  if (active) {
+    state.pollTimer = setTimeout(withErrors(loadJobs), jobPollInterval);
+  }
+}
+
+function init() {
+  $('token').value = localStorage.getItem(tokenKey) || '';
+
+  $('token-form').addEventListener('submit', (e) => {
+    e.preventDefault();
+
+    localStorage.setItem(tokenKey, $('token').value.trim());
+    showMessage('Token saved', true);
+  });
+
+  $('load-growth').addEventListener('click', withErrors(loadGrowth));
+  $('fetch-lists').addEventListener('click', withErrors(() => fetchLists($('fetch-lists'))));
+  $('load-not-mutual').addEventListener('click', withErrors(() => {
+    state.notMutualOffset = 0;
+
+    return loadNotMutual();
+  }));
+  $('not-mutual-prev').addEventListener('click', withErrors(() => {
+    state.notMutualOffset = Math.max(0, state.notMutualOffset - pageSize);
+
+    return loadNotMutual();
+  }));
+  $('not-mutual-next').addEventListener('click', withErrors(() => {
+    state.notMutualOffset += pageSize;
+
+    return loadNotMutual();
+  }));
+  $('load-jobs').addEventListener('click', withErrors(loadJobs));
+
+  if (localStorage.getItem(tokenKey)) {
+    withErrors(loadJobs)();
+  }
+}
+
+document.addEventListener('DOMContentLoaded', init);
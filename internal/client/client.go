@@ -4,9 +4,11 @@
 import (
 	"context"
 	"io"
-	"time"
 
 	"github.com/obalunenko/instadiff-cli/internal/client/instagram"
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
 	"github.com/obalunenko/instadiff-cli/internal/media"
 	"github.com/obalunenko/instadiff-cli/internal/models"
 )
@@ -19,29 +21,44 @@ type Client interface {
 	UserFollowers(ctx context.Context, user models.User) ([]models.User, error)
 	Followings(ctx context.Context) ([]models.User, error)
 	UserFollowings(ctx context.Context, user models.User) ([]models.User, error)
-	Follow(ctx context.Context, user models.User) error
+	Follow(ctx context.Context, user models.User) (models.FollowStatus, error)
 	Unfollow(ctx context.Context, user models.User) error
 	Block(ctx context.Context, user models.User) error
 	Unblock(ctx context.Context, user models.User) error
+	BlockedUsers(ctx context.Context) ([]models.User, error)
+	CloseFriends(ctx context.Context) ([]models.User, error)
+	SetCloseFriends(ctx context.Context, add, remove []models.User) error
+	PendingRequests(ctx context.Context) ([]models.User, error)
+	ApproveRequest(ctx context.Context, user models.User) error
+	DeclineRequest(ctx context.Context, user models.User) error
 	IsUseless(ctx context.Context, user models.User, threshold int) (bool, error)
-	UploadMedia(ctx context.Context, file io.Reader, mt media.Type) error
+	Posts(ctx context.Context, limit int) ([]models.Post, error)
+	Likers(ctx context.Context, post models.Post) ([]models.User, error)
+	Commenters(ctx context.Context, post models.Post) ([]models.User, error)
+	Stories(ctx context.Context) ([]models.Story, error)
+	StoryViewers(ctx context.Context, story models.Story) ([]models.User, error)
+	UploadMedia(ctx context.Context, files []io.Reader, mt media.Type, post media.PostOptions) (string, error)
+	DeletePost(ctx context.Context, post models.Post) error
+	DeleteStory(ctx context.Context, story models.Story) error
+	ArchiveMedia(ctx context.Context, id string) error
+	EditCaption(ctx context.Context, id, caption string) error
 	Logout(ctx context.Context) error
 }
 
 // Params holds Client constructor parameters.
 type Params struct {
-	SessionPath string
-	Sleep       time.Duration
-	Username    string
+	SessionPath    string
+	Username       string
+	EncryptSession bool
+	ForceLogin     bool
+	Retry          retry.Policy
+	Pacing         ratelimit.Params
+	HTTP           transport.Params
 }
 
 // New creates Client. Also returns logout func.
 func New(ctx context.Context, p Params) (Client, error) {
-	cl, err := makeInstagramClient(ctx, instagram.Params{
-		Sleep:       p.Sleep,
-		SessionPath: p.SessionPath,
-		Username:    p.Username,
-	})
+	cl, err := makeInstagramClient(ctx, makeInstagramParams(p))
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +66,48 @@ func New(ctx context.Context, p Params) (Client, error) {
 	return cl, nil
 }
 
+// SessionUsername returns account username of the stored client session without login.
+func SessionUsername(p Params) (string, error) {
+	return instagram.SessionUsername(makeInstagramParams(p))
+}
+
+// RotateSessionKey re-encrypts stored client session with a new key.
+func RotateSessionKey(ctx context.Context, p Params) error {
+	return instagram.RotateSessionKey(ctx, makeInstagramParams(p))
+}
+
+// SessionStatus returns details of the stored client session.
+func SessionStatus(ctx context.Context, p Params) (models.SessionInfo, error) {
+	return instagram.SessionStatus(ctx, makeInstagramParams(p))
+}
+
+// LogoutSession logs out stored client session and removes it.
+func LogoutSession(ctx context.Context, p Params) error {
+	return instagram.LogoutSession(ctx, makeInstagramParams(p))
+}
+
+// ExportSession exports stored client session to the file.
+func ExportSession(ctx context.Context, p Params, dst string) error {
+	return instagram.ExportSession(ctx, makeInstagramParams(p), dst)
+}
+
+// ImportSession imports client session from the file. Returns session username.
+func ImportSession(ctx context.Context, p Params, src string) (string, error) {
+	return instagram.ImportSession(ctx, makeInstagramParams(p), src)
+}
+
+func makeInstagramParams(p Params) instagram.Params {
+	return instagram.Params{
+		SessionPath:    p.SessionPath,
+		Username:       p.Username,
+		EncryptSession: p.EncryptSession,
+		ForceLogin:     p.ForceLogin,
+		Retry:          p.Retry,
+		Pacing:         p.Pacing,
+		HTTP:           p.HTTP,
+	}
+}
+
 func makeInstagramClient(ctx context.Context, params instagram.Params) (Client, error) {
 	return instagram.New(ctx, params)
 }
@@ -1,13 +1,44 @@
 // Package errors defines common client errors.
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrEmptyInput returned in case when user input is empty.
 	ErrEmptyInput = errors.New("should not be empty")
+	// ErrNotFound returned in case when requested entity not found.
+	ErrNotFound = errors.New("not found")
 	// ErrUserNotFound returned in case when user not found.
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = fmt.Errorf("user %w", ErrNotFound)
+	// ErrLocationNotFound returned in case when location search has no results.
+	ErrLocationNotFound = fmt.Errorf("location %w", ErrNotFound)
 	// ErrUnsupportedMediaType returned in case when media type is out of valid boundaries.
 	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrNoSession returned when there is no stored session.
+	ErrNoSession = errors.New("session not found")
+	// ErrInvalidSession returned when session file could not be parsed.
+	ErrInvalidSession = errors.New("invalid session")
+	// ErrRateLimited returned when social network limits requests rate.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrChallengeRequired returned when challenge (checkpoint) should be passed to continue.
+	ErrChallengeRequired = errors.New("challenge required")
+	// ErrLoginRequired returned when session is not valid anymore.
+	ErrLoginRequired = errors.New("login required")
+	// ErrPrivate returned when requested data is not available because account is private.
+	ErrPrivate = errors.New("account is private")
+	// ErrTransient returned on temporary network or server errors.
+	ErrTransient = errors.New("transient error")
 )
+
+// IsRetryable reports whether request that failed with err could be retried.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTransient)
+}
+
+// IsFatal reports whether err means that no more requests could be done with current session.
+func IsFatal(err error) bool {
+	return errors.Is(err, ErrLoginRequired) || errors.Is(err, ErrChallengeRequired)
+}
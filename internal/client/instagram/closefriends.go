@@ -0,0 +1,129 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+const (
+	urlCloseFriends    = "friendships/besties/"
+	urlSetCloseFriends = "friendships/set_besties/"
+)
+
+type closeFriendsResp struct {
+	Users []struct {
+		ID       int64  `json:"pk"`
+		Username string `json:"username"`
+		FullName string `json:"full_name"`
+	} `json:"users"`
+	NextMaxID json.RawMessage `json:"next_max_id"`
+}
+
+// CloseFriends returns close friends list of the account.
+func (c *Client) CloseFriends(ctx context.Context) ([]models.User, error) {
+	var (
+		users []models.User
+		maxID string
+	)
+
+	seen := make(map[int64]bool)
+
+	for {
+		endpoint := urlCloseFriends
+		if maxID != "" {
+			endpoint += "?" + url.Values{"max_id": {maxID}}.Encode()
+		}
+
+		var resp closeFriendsResp
+
+		err := c.do(ctx, ratelimit.ClassRead, func() error {
+			body, err := c.sendPrivate(ctx, http.MethodGet, endpoint, nil)
+			if err != nil {
+				return err
+			}
+
+			return json.Unmarshal(body, &resp)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("close friends: %w", err)
+		}
+
+		for _, u := range resp.Users {
+			if seen[u.ID] {
+				continue
+			}
+
+			seen[u.ID] = true
+
+			users = append(users, models.MakeUser(u.ID, u.Username, u.FullName))
+		}
+
+		maxID = nextMaxID(resp.NextMaxID)
+		if maxID == "" {
+			return users, nil
+		}
+	}
+}
+
+// nextMaxID returns pagination cursor, instagram sends it either as a string or as a number.
+func nextMaxID(raw json.RawMessage) string {
+	var s string
+
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var n int64
+
+	if err := json.Unmarshal(raw, &n); err == nil && n != 0 {
+		return strconv.FormatInt(n, 10)
+	}
+
+	return ""
+}
+
+// SetCloseFriends adds and removes users from close friends list in one request.
+func (c *Client) SetCloseFriends(ctx context.Context, add, remove []models.User) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	cfg := c.client.ExportConfig()
+
+	data := map[string]any{
+		"module": "CLOSE_FRIENDS_V2_SEARCH",
+		"source": "audience_manager",
+		"add":    userIDs(add),
+		"remove": userIDs(remove),
+		"_uid":   strconv.FormatInt(cfg.ID, 10),
+		"_uuid":  cfg.UUID,
+	}
+
+	err := c.do(ctx, ratelimit.ClassWrite, func() error {
+		_, err := c.sendPrivate(ctx, http.MethodPost, urlSetCloseFriends, data)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("set close friends: %w", err)
+	}
+
+	return nil
+}
+
+func userIDs(users []models.User) []string {
+	ids := make([]string, 0, len(users))
+
+	for _, u := range users {
+		ids = append(ids, strconv.FormatInt(u.ID, 10))
+	}
+
+	return ids
+}
@@ -0,0 +1,121 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/Davincible/goinsta/v3"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+)
+
+// classifyError wraps goinsta error with one of the typed client errors, so callers could
+// make decisions with errors.Is. Original error is kept in the chain.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	sentinel := errorKind(err)
+	if sentinel == nil || errors.Is(err, sentinel) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", sentinel, err)
+}
+
+func errorKind(err error) error {
+	switch {
+	case errors.Is(err, goinsta.ErrTooManyRequests):
+		return clientErrors.ErrRateLimited
+	case errors.Is(err, goinsta.ErrChallengeRequired),
+		errors.Is(err, goinsta.ErrCheckpointRequired),
+		errors.Is(err, goinsta.ErrChallengeFailed):
+		return clientErrors.ErrChallengeRequired
+	case errors.Is(err, goinsta.ErrLoginRequired),
+		errors.Is(err, goinsta.ErrLoggedOut),
+		errors.Is(err, goinsta.ErrSessionNotSet):
+		return clientErrors.ErrLoginRequired
+	case errors.Is(err, goinsta.ErrSearchUserNotFound):
+		return clientErrors.ErrUserNotFound
+	case errors.Is(err, goinsta.ErrMediaDeleted):
+		return clientErrors.ErrNotFound
+	}
+
+	var e400 goinsta.Error400
+
+	if errors.As(err, &e400) {
+		return error400Kind(e400)
+	}
+
+	var eN goinsta.ErrorN
+
+	if errors.As(err, &eN) {
+		return errorNKind(eN)
+	}
+
+	var e503 goinsta.Error503
+
+	if errors.As(err, &e503) {
+		return clientErrors.ErrTransient
+	}
+
+	if isNetworkError(err) {
+		return clientErrors.ErrTransient
+	}
+
+	return nil
+}
+
+func error400Kind(e goinsta.Error400) error {
+	msg := e.GetMessage()
+
+	switch {
+	case msg == "user_not_found":
+		return clientErrors.ErrUserNotFound
+	case msg == "rate_limit_error", msg == "feedback_required",
+		strings.Contains(strings.ToLower(e.Message), "please wait a few minutes"):
+		return clientErrors.ErrRateLimited
+	case strings.Contains(strings.ToLower(e.Message), "not authorized to view user"):
+		return clientErrors.ErrPrivate
+	case e.DebugInfo.Retriable:
+		return clientErrors.ErrTransient
+	default:
+		return nil
+	}
+}
+
+func errorNKind(e goinsta.ErrorN) error {
+	switch e.Status {
+	case "404":
+		return clientErrors.ErrNotFound
+	case "429":
+		return clientErrors.ErrRateLimited
+	case "500", "502", "504":
+		return clientErrors.ErrTransient
+	default:
+		return nil
+	}
+}
+
+// isNetworkError reports whether err is a temporary network failure. Other transport errors,
+// e.g. TLS verification or proxy misconfiguration, are permanent and not retried.
+func isNetworkError(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,184 @@
+package instagram
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Davincible/goinsta/v3"
+	"github.com/stretchr/testify/assert"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+)
+
+func Test_classifyError(t *testing.T) {
+	errUnknown := errors.New("unknown")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "too many requests",
+			err:  fmt.Errorf("wrapped: %w", goinsta.ErrTooManyRequests),
+			want: clientErrors.ErrRateLimited,
+		},
+		{
+			name: "feedback required",
+			err:  goinsta.Error400{ErrorType: "feedback_required"},
+			want: clientErrors.ErrRateLimited,
+		},
+		{
+			name: "challenge required",
+			err:  goinsta.ErrChallengeRequired,
+			want: clientErrors.ErrChallengeRequired,
+		},
+		{
+			name: "logged out",
+			err:  goinsta.ErrLoggedOut,
+			want: clientErrors.ErrLoginRequired,
+		},
+		{
+			name: "user not found",
+			err:  goinsta.Error400{ErrorType: "user_not_found"},
+			want: clientErrors.ErrUserNotFound,
+		},
+		{
+			name: "private",
+			err:  goinsta.Error400{Message: "Not authorized to view user"},
+			want: clientErrors.ErrPrivate,
+		},
+		{
+			name: "server error",
+			err:  goinsta.ErrorN{Status: "502"},
+			want: clientErrors.ErrTransient,
+		},
+		{
+			name: "service unavailable",
+			err:  goinsta.Error503{Message: "try later"},
+			want: clientErrors.ErrTransient,
+		},
+		{
+			name: "unexpected EOF",
+			err:  fmt.Errorf("read body: %w", io.ErrUnexpectedEOF),
+			want: clientErrors.ErrTransient,
+		},
+		{
+			name: "context canceled",
+			err:  context.Canceled,
+			want: context.Canceled,
+		},
+		{
+			name: "unknown",
+			err:  errUnknown,
+			want: errUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			if tt.want == nil {
+				assert.NoError(t, got)
+
+				return
+			}
+
+			assert.ErrorIs(t, got, tt.want)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClient_do_NetworkErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantAttempts int
+	}{
+		{
+			name: "certificate error",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://i.instagram.com/api/v1/",
+				Err: x509.UnknownAuthorityError{},
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "unsupported proxy scheme",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://i.instagram.com/api/v1/",
+				Err: errors.New("proxyconnect tcp: unsupported proxy scheme"),
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "timeout",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://i.instagram.com/api/v1/",
+				Err: timeoutError{},
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "connection reset",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://i.instagram.com/api/v1/",
+				Err: fmt.Errorf("read: %w", syscall.ECONNRESET),
+			},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				retry: retry.Policy{
+					MaxAttempts:    2,
+					BaseDelay:      time.Millisecond,
+					RateLimitDelay: time.Millisecond,
+					MaxDelay:       time.Millisecond,
+				},
+			}
+
+			var attempts int
+
+			err := c.do(context.Background(), ratelimit.ClassRead, func() error {
+				attempts++
+
+				return tt.err
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
@@ -6,10 +6,10 @@
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/Davincible/goinsta/v3"
 	log "github.com/obalunenko/logger"
@@ -17,8 +17,12 @@
 
 	"github.com/obalunenko/instadiff-cli/internal/actions"
 	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
 	"github.com/obalunenko/instadiff-cli/internal/media"
 	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/internal/session"
 	"github.com/obalunenko/instadiff-cli/pkg/spinner"
 )
 
@@ -26,37 +30,70 @@
 type Client struct {
 	client   *goinsta.Instagram
 	sessFile string
-	sleep    time.Duration
+	sessKey  []byte
+	retry    retry.Policy
+	limiter  *ratelimit.Limiter
+	// http is goinsta HTTP client, used for requests that goinsta doesn't cover.
+	http *http.Client
 }
 
 // Params holds Client constructor parameters.
 type Params struct {
-	Sleep       time.Duration
-	SessionPath string
-	Username    string
+	SessionPath    string
+	Username       string
+	EncryptSession bool
+	// ForceLogin skips stored session import and logs in with password.
+	ForceLogin bool
+	// Retry is a policy for retrying failed requests.
+	Retry retry.Policy
+	// Pacing holds limits for requests rate.
+	Pacing ratelimit.Params
+	// HTTP holds HTTP transport settings: proxy, timeouts, TLS or custom http.RoundTripper.
+	HTTP transport.Params
 }
 
 // New Client constructor.
 func New(ctx context.Context, p Params) (*Client, error) {
-	var (
-		err   error
-		uname string
-	)
+	uname, err := getUsername(p)
+	if err != nil {
+		return nil, err
+	}
 
-	uname = p.Username
+	sessFile := sessionFile(p.SessionPath, uname)
 
-	if uname == "" {
-		uname, err = usernameInput()
-		if err != nil {
-			return nil, fmt.Errorf("username: %w", err)
-		}
+	sessKey, err := sessionKey(sessFile, p.EncryptSession)
+	if err != nil {
+		return nil, fmt.Errorf("session key: %w", err)
 	}
 
-	sessFile := filepath.Join(p.SessionPath, fmt.Sprintf("%s.sess", uname))
+	cl, err := openSession(ctx, uname, sessFile, sessKey, p.HTTP.ForAccount(uname), p.ForceLogin)
+	if err != nil {
+		return nil, err
+	}
 
-	cl, err := importFromFile(ctx, sessFile)
-	if err == nil {
-		return cl, nil
+	cl.retry = p.Retry
+	cl.limiter = ratelimit.New(p.Pacing)
+
+	return cl, nil
+}
+
+// openSession imports stored session or logs in with password when it is not possible.
+func openSession(
+	ctx context.Context,
+	uname, sessFile string,
+	sessKey []byte,
+	hp transport.Params,
+	forceLogin bool,
+) (*Client, error) {
+	if !forceLogin {
+		cl, err := importFromFile(ctx, sessFile, sessKey, hp)
+		if err == nil {
+			return cl, nil
+		}
+
+		if errors.Is(err, session.ErrWrongKey) {
+			return nil, fmt.Errorf("import session: %w", err)
+		}
 	}
 
 	pwd, err := passwordInput()
@@ -64,7 +101,7 @@ func New(ctx context.Context, p Params) (*Client, error) {
 		return nil, fmt.Errorf("password: %w", err)
 	}
 
-	cl, err = login(ctx, uname, pwd, sessFile)
+	cl, err := login(ctx, uname, pwd, sessFile, sessKey, hp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to login: %w", err)
 	}
@@ -72,10 +109,26 @@ func New(ctx context.Context, p Params) (*Client, error) {
 	return cl, nil
 }
 
-func importFromFile(ctx context.Context, sessFile string) (*Client, error) {
+func getUsername(p Params) (string, error) {
+	if p.Username != "" {
+		return p.Username, nil
+	}
+
+	uname, err := usernameInput()
+	if err != nil {
+		return "", fmt.Errorf("username: %w", err)
+	}
+
+	return uname, nil
+}
+
+func importFromFile(ctx context.Context, sessFile string, sessKey []byte, hp transport.Params) (*Client, error) {
 	stop := spinner.Set("Trying to import previous session..", "", "yellow")
 
-	i, err := goinsta.Import(sessFile)
+	i, hc, err := importSession(sessFile, sessKey, hp)
+	if err == nil {
+		err = i.Account.Sync()
+	}
 
 	stop()
 
@@ -85,10 +138,16 @@ func importFromFile(ctx context.Context, sessFile string) (*Client, error) {
 
 	log.WithField(ctx, "session_file", sessFile).Info("Session imported")
 
-	return syncInstagram(ctx, i, sessFile)
+	return syncInstagram(ctx, i, hc, sessFile, sessKey)
 }
 
-func syncInstagram(ctx context.Context, cli *goinsta.Instagram, sessFile string) (*Client, error) {
+func syncInstagram(
+	ctx context.Context,
+	cli *goinsta.Instagram,
+	hc *http.Client,
+	sessFile string,
+	sessKey []byte,
+) (*Client, error) {
 	stop := spinner.Set("Refreshing account info", "", "yellow")
 
 	if err := cli.OpenApp(); err != nil {
@@ -99,7 +158,7 @@ func syncInstagram(ctx context.Context, cli *goinsta.Instagram, sessFile string)
 
 	stop = spinner.Set("Exporting session", "", "yellow")
 
-	if err := cli.Export(sessFile); err != nil {
+	if err := exportSession(cli, sessFile, sessKey); err != nil {
 		log.WithError(ctx, err).Error("Failed to save session")
 	}
 
@@ -108,15 +167,22 @@ func syncInstagram(ctx context.Context, cli *goinsta.Instagram, sessFile string)
 	return &Client{
 		client:   cli,
 		sessFile: sessFile,
+		sessKey:  sessKey,
+		http:     hc,
 	}, nil
 }
 
-func login(ctx context.Context, uname, pwd, sessFile string) (*Client, error) {
+func login(ctx context.Context, uname, pwd, sessFile string, sessKey []byte, hp transport.Params) (*Client, error) {
 	insta := goinsta.New(uname, pwd)
 
+	hc, err := applyTransport(insta, hp)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := spinner.Set("Sending log in request..", "", "yellow")
 
-	err := insta.Login()
+	err = insta.Login()
 
 	stop()
 
@@ -125,7 +191,7 @@ func login(ctx context.Context, uname, pwd, sessFile string) (*Client, error) {
 		return nil, err
 	}
 
-	return syncInstagram(ctx, insta, sessFile)
+	return syncInstagram(ctx, insta, hc, sessFile, sessKey)
 }
 
 func maybeChallengeRequired(insta *goinsta.Instagram, err error) (*goinsta.Instagram, error) {
@@ -178,6 +244,12 @@ func passwordInput() (string, error) {
 	return getPrompt(ask, key)
 }
 
+func passphraseInput(ask string) (string, error) {
+	key := "passphrase"
+
+	return getMaskedPrompt(ask, key)
+}
+
 func twoFactorCode() (string, error) {
 	ask := "What is your two factor code?"
 	key := "2fa code"
@@ -186,6 +258,14 @@ func twoFactorCode() (string, error) {
 }
 
 func getPrompt(ask, key string) (string, error) {
+	return prompt(ask, key, false)
+}
+
+func getMaskedPrompt(ask, key string) (string, error) {
+	return prompt(ask, key, true)
+}
+
+func prompt(ask, key string, mask bool) (string, error) {
 	ui := &input.UI{
 		Writer: os.Stdout,
 		Reader: os.Stdin,
@@ -199,7 +279,7 @@ func getPrompt(ask, key string) (string, error) {
 			HideDefault: false,
 			HideOrder:   false,
 			Hide:        false,
-			Mask:        false,
+			Mask:        mask,
 			MaskDefault: false,
 			MaskVal:     "",
 			ValidateFunc: func(s string) error {
@@ -241,46 +321,159 @@ func challenge(cl *goinsta.Instagram, chURL string) (*goinsta.Instagram, error)
 	return cl, nil
 }
 
-// UploadMedia uploads media to the profile.
-func (c *Client) UploadMedia(ctx context.Context, file io.Reader, mt media.Type) error {
+// UploadMedia uploads media to the profile. Carousel and multiple story videos are uploaded as an album.
+// Tagged users and location are looked up by name before upload. Returns id of the created media.
+func (c *Client) UploadMedia(ctx context.Context, files []io.Reader, mt media.Type, post media.PostOptions) (string, error) {
 	if !mt.Valid() {
-		return fmt.Errorf("%s: %w", mt.String(), clientErrors.ErrUnsupportedMediaType)
+		return "", fmt.Errorf("%s: %w", mt.String(), clientErrors.ErrUnsupportedMediaType)
+	}
+
+	if err := mt.CheckFilesNum(len(files)); err != nil {
+		return "", err
+	}
+
+	opts := uploadOptions(files, mt, post)
+
+	tags, err := c.userTags(ctx, post.UserTags, len(files))
+	if err != nil {
+		return "", fmt.Errorf("user tags: %w", err)
+	}
+
+	switch {
+	case len(tags) == 0:
+	case opts.Album != nil:
+		opts.AlbumTags = &tags
+	default:
+		opts.UserTags = &tags[0]
+	}
+
+	if post.Location != "" {
+		if opts.Location, err = c.location(ctx, post.Location); err != nil {
+			return "", fmt.Errorf("location: %w", err)
+		}
+	}
+
+	if err = c.limiter.Wait(ctx, ratelimit.ClassWrite); err != nil {
+		return "", err
 	}
 
-	itm, err := c.client.Upload(&goinsta.UploadOptions{
-		File:                 file,
+	itm, err := c.client.Upload(opts)
+
+	err = classifyError(err)
+
+	c.limiter.Observe(err)
+
+	if err != nil {
+		// upload is not idempotent, so it is not retried.
+		return "", err
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"id":              itm.ID,
+		"code":            itm.Code,
+		"media_type":      mt.String(),
+		"media_type_inst": itm.MediaType,
+		"is_story":        opts.IsStory,
+	}).Debug("Uploaded")
+
+	return itm.GetID(), nil
+}
+
+// uploadOptions maps files onto goinsta upload options: several files are sent as an album,
+// which is a carousel for feed and several stories for story video.
+func uploadOptions(files []io.Reader, mt media.Type, post media.PostOptions) *goinsta.UploadOptions {
+	opts := &goinsta.UploadOptions{
+		File:                 nil,
 		Thumbnail:            nil,
 		Album:                nil,
-		Caption:              "",
-		IsStory:              isStory(mt),
+		Caption:              post.FullCaption(),
+		IsStory:              mt.IsStory(),
 		MuteAudio:            false,
-		DisableComments:      false,
-		DisableLikeViewCount: false,
+		DisableComments:      post.DisableComments,
+		DisableLikeViewCount: post.HideLikeCount,
 		DisableSubtitles:     false,
 		UserTags:             nil,
 		AlbumTags:            nil,
 		Location:             nil,
+	}
+
+	if mt == media.TypeCarousel || len(files) > 1 {
+		opts.Album = files
+	} else {
+		opts.File = files[0]
+	}
+
+	return opts
+}
+
+// userTags looks up tagged users and groups tags by file. Tag without position is placed in the center.
+func (c *Client) userTags(ctx context.Context, tags []media.UserTag, files int) ([][]goinsta.UserTag, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	result := make([][]goinsta.UserTag, files)
+
+	for _, t := range tags {
+		u, err := c.profileByName(ctx, t.Username)
+		if err != nil {
+			return nil, fmt.Errorf("get user [%s]: %w", t.Username, err)
+		}
+
+		pos := [2]float64{t.X, t.Y}
+		if t.X == 0 && t.Y == 0 {
+			pos = [2]float64{0.5, 0.5}
+		}
+
+		i := 0
+		if t.File > 0 {
+			i = t.File - 1
+		}
+
+		result[i] = append(result[i], goinsta.UserTag{User: u, Position: pos})
+	}
+
+	return result, nil
+}
+
+// location returns the first place found by the name.
+func (c *Client) location(ctx context.Context, name string) (*goinsta.LocationTag, error) {
+	var res *goinsta.SearchResult
+
+	err := c.do(ctx, ratelimit.ClassRead, func() error {
+		var err error
+
+		res, err = c.client.Searchbar.SearchLocation(name, true)
+
+		return err
 	})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("search [%s]: %w", name, err)
 	}
 
-	log.WithFields(ctx, log.Fields{
-		"id":              itm.ID,
-		"media_type_inst": itm.MediaType,
-		"is_story":        isStory,
-	}).Debug("Uploaded")
+	for _, p := range res.Places {
+		if p.Location == nil {
+			continue
+		}
 
-	return nil
-}
+		l := p.Location
+
+		return &goinsta.LocationTag{
+			Name:           l.Name,
+			Address:        l.Address,
+			Lat:            l.Lat,
+			Lng:            l.Lng,
+			ExternalSource: l.ExternalSource,
+			PlacesID:       strconv.FormatInt(l.FacebookPlacesID, 10),
+		}, nil
+	}
 
-func isStory(mt media.Type) bool {
-	return mt == media.TypeStoryPhoto
+	return nil, fmt.Errorf("[%s]: %w", name, clientErrors.ErrLocationNotFound)
 }
 
 // IsUseless reports where user is useless for statistics.
 func (c *Client) IsUseless(ctx context.Context, user models.User, threshold int) (bool, error) {
-	u, err := c.client.Profiles.ByName(user.UserName)
+	u, err := c.profileByName(ctx, user.UserName)
 	if err != nil {
 		return false, err
 	}
@@ -290,9 +483,7 @@ func (c *Client) IsUseless(ctx context.Context, user models.User, threshold int)
 		return false, err
 	}
 
-	u.SetInstagram(c.client)
-
-	if err := u.Info(); err != nil {
+	if err = c.do(ctx, ratelimit.ClassRead, func() error { return u.Info() }); err != nil {
 		return false, fmt.Errorf("update info: %w", err)
 	}
 
@@ -307,44 +498,71 @@ func (c *Client) IsUseless(ctx context.Context, user models.User, threshold int)
 
 // UserFollowers returns user followers.
 func (c *Client) UserFollowers(ctx context.Context, user models.User) ([]models.User, error) {
-	u, err := c.client.Profiles.ByName(user.UserName)
+	u, err := c.profileByName(ctx, user.UserName)
 	if err != nil {
 		return nil, err
 	}
 
-	u.SetInstagram(c.client)
-
-	return c.makeUsersList(ctx, u.Followers(""))
+	return c.makeUsersList(ctx, func() *goinsta.Users {
+		return u.Followers("")
+	})
 }
 
 // UserFollowings returns user followings.
 func (c *Client) UserFollowings(ctx context.Context, user models.User) ([]models.User, error) {
-	u, err := c.client.Profiles.ByName(user.UserName)
+	u, err := c.profileByName(ctx, user.UserName)
 	if err != nil {
 		return nil, err
 	}
 
-	u.SetInstagram(c.client)
-
-	return c.makeUsersList(ctx, u.Following("", goinsta.EarliestOrder))
+	return c.makeUsersList(ctx, func() *goinsta.Users {
+		return u.Following("", goinsta.EarliestOrder)
+	})
 }
 
 // GetUserByName finds user by username.
-func (c *Client) GetUserByName(_ context.Context, username string) (models.User, error) {
-	u, err := c.client.Profiles.ByName(username)
+func (c *Client) GetUserByName(ctx context.Context, username string) (models.User, error) {
+	u, err := c.profileByName(ctx, username)
 	if err != nil {
-		if isErrUserNotFound(err) {
-			return models.User{}, clientErrors.ErrUserNotFound
-		}
-
 		return models.User{}, err
 	}
 
 	return models.MakeUser(u.ID, u.Username, u.FullName), nil
 }
 
-func isErrUserNotFound(err error) bool {
-	return strings.Contains(err.Error(), "user_not_found")
+func (c *Client) profileByName(ctx context.Context, username string) (*goinsta.User, error) {
+	var u *goinsta.User
+
+	err := c.do(ctx, ratelimit.ClassRead, func() error {
+		var err error
+
+		u, err = c.client.Profiles.ByName(username)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.SetInstagram(c.client)
+
+	return u, nil
+}
+
+// do calls f paced by the limiter for passed class of request and with retry policy.
+// Returned error is classified.
+func (c *Client) do(ctx context.Context, class ratelimit.Class, f func() error) error {
+	return c.retry.Do(ctx, func() error {
+		if err := c.limiter.Wait(ctx, class); err != nil {
+			return err
+		}
+
+		err := classifyError(f())
+
+		c.limiter.Observe(err)
+
+		return err
+	})
 }
 
 // Block user.
@@ -357,9 +575,25 @@ func (c *Client) Unblock(ctx context.Context, user models.User) error {
 	return c.actUser(ctx, user, actions.UserActionUnblock)
 }
 
-// Follow user.
-func (c *Client) Follow(ctx context.Context, user models.User) error {
-	return c.actUser(ctx, user, actions.UserActionFollow)
+// Follow user. Returns whether user is followed or follow request waits for approval.
+func (c *Client) Follow(ctx context.Context, user models.User) (models.FollowStatus, error) {
+	fs, err := c.actUserFriendship(ctx, user, actions.UserActionFollow)
+	if err != nil {
+		return models.FollowStatusUnknown, err
+	}
+
+	return followStatus(fs), nil
+}
+
+func followStatus(fs goinsta.Friendship) models.FollowStatus {
+	switch {
+	case fs.Following:
+		return models.FollowStatusFollowing
+	case fs.OutgoingRequest:
+		return models.FollowStatusRequested
+	default:
+		return models.FollowStatusUnknown
+	}
 }
 
 // Unfollow user.
@@ -367,14 +601,76 @@ func (c *Client) Unfollow(ctx context.Context, user models.User) error {
 	return c.actUser(ctx, user, actions.UserActionUnfollow)
 }
 
+// ApproveRequest approves incoming follow request.
+func (c *Client) ApproveRequest(ctx context.Context, user models.User) error {
+	return c.actUser(ctx, user, actions.UserActionApprove)
+}
+
+// DeclineRequest declines incoming follow request.
+func (c *Client) DeclineRequest(ctx context.Context, user models.User) error {
+	return c.actUser(ctx, user, actions.UserActionDecline)
+}
+
+// PendingRequests returns users that requested to follow the account.
+func (c *Client) PendingRequests(ctx context.Context) ([]models.User, error) {
+	var pr *goinsta.PendingRequests
+
+	err := c.do(ctx, ratelimit.ClassRead, func() error {
+		var err error
+
+		pr, err = c.client.Account.PendingFollowRequests()
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pending requests: %w", err)
+	}
+
+	users := make([]models.User, 0, len(pr.Users))
+
+	for _, u := range pr.Users {
+		users = append(users, models.MakeUser(u.ID, u.Username, u.FullName))
+	}
+
+	return users, nil
+}
+
+// BlockedUsers returns users blocked by the account.
+func (c *Client) BlockedUsers(ctx context.Context) ([]models.User, error) {
+	var blocked []goinsta.BlockedUser
+
+	err := c.do(ctx, ratelimit.ClassRead, func() error {
+		var err error
+
+		blocked, err = c.client.Profiles.Blocked()
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blocked users: %w", err)
+	}
+
+	users := make([]models.User, 0, len(blocked))
+
+	for _, u := range blocked {
+		users = append(users, models.MakeUser(u.UserID, u.Username, u.FullName))
+	}
+
+	return users, nil
+}
+
 // Followers returns list of followers.
 func (c *Client) Followers(ctx context.Context) ([]models.User, error) {
-	return c.makeUsersList(ctx, c.client.Account.Followers(""))
+	return c.makeUsersList(ctx, func() *goinsta.Users {
+		return c.client.Account.Followers("")
+	})
 }
 
 // Followings returns list of followings.
 func (c *Client) Followings(ctx context.Context) ([]models.User, error) {
-	return c.makeUsersList(ctx, c.client.Account.Following("", goinsta.EarliestOrder))
+	return c.makeUsersList(ctx, func() *goinsta.Users {
+		return c.client.Account.Following("", goinsta.EarliestOrder)
+	})
 }
 
 // Username returns current account username.
@@ -383,8 +679,15 @@ func (c *Client) Username(_ context.Context) string {
 }
 
 func (c *Client) actUser(ctx context.Context, user models.User, act actions.UserAction) error {
+	_, err := c.actUserFriendship(ctx, user, act)
+
+	return err
+}
+
+// actUserFriendship does action on user and returns updated friendship status.
+func (c *Client) actUserFriendship(ctx context.Context, user models.User, act actions.UserAction) (goinsta.Friendship, error) {
 	if ctx.Err() != nil {
-		return ctx.Err()
+		return goinsta.Friendship{}, ctx.Err()
 	}
 
 	us := goinsta.User{
@@ -407,15 +710,23 @@ func (c *Client) actUser(ctx context.Context, user models.User, act actions.User
 		}
 	case actions.UserActionUnblock:
 		f = us.Unblock
+	case actions.UserActionApprove, actions.UserActionDecline:
+		// request is known to be pending, goinsta checks it before sending.
+		us.Friendship.IncomingRequest = true
+
+		f = us.ApprovePending
+		if act == actions.UserActionDecline {
+			f = us.IgnorePending
+		}
 	default:
-		return fmt.Errorf("unsupported user action type: %s", act.String())
+		return goinsta.Friendship{}, fmt.Errorf("unsupported user action type: %s", act.String())
 	}
 
-	if err := f(); err != nil {
-		return fmt.Errorf("action[%s]: %w", act.String(), err)
+	if err := c.do(ctx, ratelimit.ClassWrite, f); err != nil {
+		return goinsta.Friendship{}, fmt.Errorf("action[%s]: %w", act.String(), err)
 	}
 
-	return nil
+	return us.Friendship, nil
 }
 
 // Logout clean session and send logout request.
@@ -442,20 +753,51 @@ func (c *Client) Logout(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) makeUsersList(ctx context.Context, users *goinsta.Users) ([]models.User, error) {
+// makeUsersList iterates over all pages of users list. newUsers should return new iterator,
+// it is used to resume iteration from the failed page, as goinsta iterator could not be reused after error.
+func (c *Client) makeUsersList(ctx context.Context, newUsers func() *goinsta.Users) ([]models.User, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	seen := make(map[int64]bool, len(users.Users))
+	users := newUsers()
+
+	seen := make(map[int64]bool)
+
+	var usersList []models.User
+
+	for {
+		// last page was already fetched.
+		if errors.Is(users.Error(), goinsta.ErrNoMore) {
+			break
+		}
+
+		var hasNext bool
+
+		err := c.do(ctx, ratelimit.ClassRead, func() error {
+			hasNext = users.Next()
+			if hasNext {
+				return nil
+			}
+
+			err := users.Error()
+			if err == nil || errors.Is(err, goinsta.ErrNoMore) {
+				return nil
+			}
+
+			cursor := users.NextID
 
-	usersList := make([]models.User, 0, len(users.Users))
+			users = newUsers()
+			users.NextID = cursor
 
-	var itr int
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("users iterate: %w", err)
+		}
 
-	for users.Next() {
-		if itr != 0 {
-			time.Sleep(c.sleep)
+		if !hasNext {
+			break
 		}
 
 		for i := range users.Users {
@@ -471,14 +813,17 @@ func (c *Client) makeUsersList(ctx context.Context, users *goinsta.Users) ([]mod
 			seen[u.ID] = true
 		}
 
-		itr++
-	}
-
-	if err := users.Error(); err != nil {
-		if !errors.Is(err, goinsta.ErrNoMore) {
-			return nil, fmt.Errorf("users iterate: %w", err)
+		// goinsta keeps previous cursor when page has no next one, so last page would be requested again.
+		if isLastPage(users) {
+			break
 		}
 	}
 
 	return usersList, nil
 }
+
+func isLastPage(users *goinsta.Users) bool {
+	next := strings.TrimSpace(string(users.RawNextID))
+
+	return next == "" || next == "null" || next == `""`
+}
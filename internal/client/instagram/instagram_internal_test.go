@@ -0,0 +1,449 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport/cassette"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/internal/session"
+)
+
+// newReplayClient creates Client with stored test session that serves requests from the cassette
+// testdata/cassettes/<name>.json. Cassettes could be recorded with INSTADIFF_HTTP_RECORD environment variable.
+func newReplayClient(t testing.TB, name string) (*Client, *cassette.Replayer) {
+	t.Helper()
+
+	t.Setenv(session.EnvKey, "")
+
+	content, err := os.ReadFile(filepath.Join("testdata", "session.json"))
+	require.NoError(t, err)
+
+	sessFile := sessionFile(t.TempDir(), "tester")
+
+	require.NoError(t, session.Write(sessFile, nil, content))
+
+	c, err := cassette.Load(filepath.Join("testdata", "cassettes", name+".json"))
+	require.NoError(t, err)
+
+	rp := cassette.NewReplayer(c)
+
+	insta, hc, err := importSession(sessFile, nil, transport.Params{RoundTripper: rp})
+	require.NoError(t, err)
+
+	return &Client{
+		client:   insta,
+		sessFile: sessFile,
+		sessKey:  nil,
+		retry: retry.Policy{
+			MaxAttempts:    2,
+			BaseDelay:      time.Millisecond,
+			RateLimitDelay: time.Millisecond,
+			MaxDelay:       time.Millisecond,
+		},
+		limiter: nil,
+		http:    hc,
+	}, rp
+}
+
+func TestClient_GetUserByName(t *testing.T) {
+	c, rp := newReplayClient(t, "user_by_name")
+
+	got, err := c.GetUserByName(context.Background(), "john")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.MakeUser(42, "john", "John Doe"), got)
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_Followers(t *testing.T) {
+	c, rp := newReplayClient(t, "followers")
+
+	got, err := c.Followers(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(10, "alice", "Alice"),
+		models.MakeUser(11, "bob", "Bob"),
+		models.MakeUser(12, "carol", "Carol"),
+	}, got)
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_Followings(t *testing.T) {
+	// second page fails with transient error and is requested again from the same cursor.
+	c, rp := newReplayClient(t, "followings")
+
+	got, err := c.Followings(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(20, "dave", "Dave"),
+		models.MakeUser(21, "erin", "Erin"),
+	}, got)
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_IsUseless(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		want      bool
+	}{
+		{
+			name:      "too many followings",
+			threshold: 3,
+			want:      true,
+		},
+		{
+			name:      "regular user",
+			threshold: 10,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			c, rp := newReplayClient(t, "is_useless")
+
+			got, err := c.IsUseless(context.Background(), models.MakeUser(30, "spammer", "Spammer"), tt.threshold)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, 0, rp.Left())
+		})
+	}
+}
+
+func TestClient_Actions(t *testing.T) {
+	c, rp := newReplayClient(t, "actions")
+
+	ctx := context.Background()
+	user := models.MakeUser(40, "frank", "Frank")
+
+	status, err := c.Follow(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, models.FollowStatusFollowing, status)
+
+	require.NoError(t, c.Unfollow(ctx, user))
+	require.NoError(t, c.Block(ctx, user))
+	require.NoError(t, c.Unblock(ctx, user))
+
+	// account became private, follow request waits for approval.
+	status, err = c.Follow(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, models.FollowStatusRequested, status)
+
+	assert.Equal(t, 0, rp.Left())
+
+	// all interactions are served, next request fails.
+	_, err = c.Follow(ctx, user)
+	require.ErrorIs(t, err, cassette.ErrNoInteraction)
+}
+
+func TestClient_UploadMedia(t *testing.T) {
+	photo := "https://i.instagram.com/rupload_igphoto/*"
+
+	tests := []struct {
+		name     string
+		cassette string
+		files    int
+		mt       media.Type
+		want     []string
+		wantID   string
+	}{
+		{
+			name:     "story photo",
+			cassette: "upload_story_photo",
+			files:    1,
+			mt:       media.TypeStoryPhoto,
+			want:     []string{photo, "https://i.instagram.com/api/v1/media/configure_to_story/"},
+			wantID:   "3000000000000000000_1",
+		},
+		{
+			name:     "feed photo",
+			cassette: "upload_feed_photo",
+			files:    1,
+			mt:       media.TypeFeedPhoto,
+			want:     []string{photo, "https://i.instagram.com/api/v1/media/configure/"},
+			wantID:   "3000000000000000000_1",
+		},
+		{
+			name:     "carousel",
+			cassette: "upload_carousel",
+			files:    3,
+			mt:       media.TypeCarousel,
+			want:     []string{photo, photo, photo, "https://i.instagram.com/api/v1/media/configure_sidecar/"},
+			wantID:   "3000000000000000000_1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c, rp := newReplayClient(t, tt.cassette)
+
+			ctx := context.Background()
+
+			files := make([]io.Reader, 0, tt.files)
+
+			for i := 0; i < tt.files; i++ {
+				files = append(files, testJPEG(t))
+			}
+
+			id, err := c.UploadMedia(ctx, files, tt.mt, media.PostOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, id)
+
+			served := rp.Served()
+
+			got := make([]string, 0, len(served))
+
+			for _, s := range served {
+				got = append(got, s.URL)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_UploadMedia_Invalid(t *testing.T) {
+	c, rp := newReplayClient(t, "upload_story_photo")
+
+	ctx := context.Background()
+
+	_, err := c.UploadMedia(ctx, []io.Reader{bytes.NewReader(nil)}, media.TypeUndefined, media.PostOptions{})
+	require.ErrorIs(t, err, clientErrors.ErrUnsupportedMediaType)
+
+	_, err = c.UploadMedia(ctx, []io.Reader{testJPEG(t)}, media.TypeCarousel, media.PostOptions{})
+	require.ErrorIs(t, err, media.ErrFilesNum)
+
+	assert.Empty(t, rp.Served())
+}
+
+func testJPEG(t testing.TB) *bytes.Reader {
+	t.Helper()
+
+	const size = 64
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestClient_PendingRequests(t *testing.T) {
+	c, rp := newReplayClient(t, "pending_requests")
+
+	ctx := context.Background()
+
+	got, err := c.PendingRequests(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(50, "grace", "Grace"),
+		models.MakeUser(51, "bot_1234", ""),
+	}, got)
+
+	require.NoError(t, c.ApproveRequest(ctx, got[0]))
+	require.NoError(t, c.DeclineRequest(ctx, got[1]))
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_BlockedUsers(t *testing.T) {
+	c, rp := newReplayClient(t, "blocked")
+
+	got, err := c.BlockedUsers(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(70, "spammer", "Spam Bot"),
+		models.MakeUser(71, "troll", ""),
+	}, got)
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_CloseFriends(t *testing.T) {
+	c, rp := newReplayClient(t, "close_friends")
+
+	ctx := context.Background()
+
+	got, err := c.CloseFriends(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(80, "heidi", "Heidi"),
+		models.MakeUser(81, "ivan", "Ivan"),
+		models.MakeUser(82, "judy", ""),
+	}, got)
+
+	// first attempt is rate limited and retried.
+	require.NoError(t, c.SetCloseFriends(ctx, got[:1], got[2:]))
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_Posts(t *testing.T) {
+	c, rp := newReplayClient(t, "posts")
+
+	ctx := context.Background()
+
+	got, err := c.Posts(ctx, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.Post{
+		{ID: "301_1", Code: "Cpost3", TakenAt: time.Unix(1700200000, 0), Likes: 2, Comments: 2, Caption: "Sunset", MediaType: "PHOTO"},
+		{ID: "302_1", Code: "Cpost2", TakenAt: time.Unix(1700100000, 0), Likes: 1, Comments: 0, MediaType: "CAROUSEL"},
+		{ID: "303_1", Code: "Cpost1", TakenAt: time.Unix(1700000000, 0), Likes: 0, Comments: 1, MediaType: "PHOTO"},
+	}, got)
+
+	likers, err := c.Likers(ctx, got[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(10, "alice", "Alice"),
+		models.MakeUser(11, "bob", "Bob"),
+	}, likers)
+
+	// second page is rate limited and retried.
+	commenters, err := c.Commenters(ctx, got[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(10, "alice", "Alice"),
+		models.MakeUser(12, "carol", ""),
+	}, commenters)
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_Stories(t *testing.T) {
+	c, rp := newReplayClient(t, "stories")
+
+	ctx := context.Background()
+
+	got, err := c.Stories(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.Story{
+		{ID: "401_1", TakenAt: time.Unix(1700000000, 0), ExpiresAt: time.Unix(1700086400, 0), Views: 3, MediaType: "PHOTO"},
+		{ID: "402_1", TakenAt: time.Unix(1700003600, 0), ExpiresAt: time.Unix(1700090000, 0), Views: 0, MediaType: "PHOTO"},
+	}, got)
+
+	viewers, err := c.StoryViewers(ctx, got[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(10, "alice", "Alice"),
+		models.MakeUser(11, "bob", "Bob"),
+		models.MakeUser(12, "carol", ""),
+	}, viewers)
+
+	// no active stories.
+	got, err = c.Stories(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_ManageMedia(t *testing.T) {
+	c, rp := newReplayClient(t, "media")
+
+	ctx := context.Background()
+
+	// delete is matched by media type in the query, instagram rejects it without one.
+	err := c.DeletePost(ctx, models.Post{ID: "501_1", MediaType: "PHOTO"})
+	require.ErrorIs(t, err, cassette.ErrNoInteraction)
+
+	require.NoError(t, c.DeletePost(ctx, models.Post{ID: "501_1", MediaType: "CAROUSEL"}))
+	require.NoError(t, c.DeleteStory(ctx, models.Story{ID: "504_1", MediaType: "VIDEO"}))
+	// first attempt is rate limited and retried.
+	require.NoError(t, c.ArchiveMedia(ctx, "502_1"))
+	require.NoError(t, c.EditCaption(ctx, "503_1", "New caption"))
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func TestClient_UploadMedia_PostOptions(t *testing.T) {
+	c, rp := newReplayClient(t, "upload_feed_photo_post")
+
+	ctx := context.Background()
+
+	post := media.PostOptions{
+		Caption:         "Sunset",
+		Hashtags:        []string{"sea"},
+		UserTags:        []media.UserTag{{Username: "john"}},
+		Location:        "Lisbon",
+		DisableComments: true,
+		HideLikeCount:   false,
+	}
+
+	_, err := c.UploadMedia(ctx, []io.Reader{testJPEG(t)}, media.TypeFeedPhoto, post)
+	require.NoError(t, err)
+
+	served := rp.Served()
+	require.Len(t, served, 4)
+	assert.Equal(t, "https://i.instagram.com/api/v1/users/john/usernameinfo/", served[0].URL)
+	assert.Equal(t, "https://i.instagram.com/api/v1/fbsearch/places/", served[1].URL)
+	assert.Equal(t, "https://i.instagram.com/api/v1/media/configure/", served[3].URL)
+
+	// location is resolved before upload, so nothing is uploaded.
+	_, err = c.UploadMedia(ctx, []io.Reader{testJPEG(t)}, media.TypeFeedPhoto, media.PostOptions{Location: "Nowhere"})
+	require.ErrorIs(t, err, clientErrors.ErrLocationNotFound)
+
+	assert.Equal(t, 0, rp.Left())
+}
+
+func Test_uploadOptions(t *testing.T) {
+	post := media.PostOptions{
+		Caption:         "Sunset",
+		Hashtags:        []string{"sea"},
+		DisableComments: true,
+		HideLikeCount:   true,
+	}
+
+	a, b := bytes.NewReader(nil), bytes.NewReader(nil)
+
+	got := uploadOptions([]io.Reader{a}, media.TypeFeedPhoto, post)
+	assert.Equal(t, "Sunset\n\n#sea", got.Caption)
+	assert.True(t, got.DisableComments)
+	assert.True(t, got.DisableLikeViewCount)
+	assert.False(t, got.IsStory)
+	assert.Equal(t, a, got.File)
+	assert.Nil(t, got.Album)
+
+	got = uploadOptions([]io.Reader{a, b}, media.TypeStoryVideo, media.PostOptions{})
+	assert.True(t, got.IsStory)
+	assert.Nil(t, got.File)
+	assert.Equal(t, []io.Reader{a, b}, got.Album)
+}
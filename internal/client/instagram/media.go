@@ -0,0 +1,117 @@
+package instagram
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Davincible/goinsta/v3"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// goinsta manages only media items it fetched itself, so own media is managed by id directly.
+const (
+	urlMediaDelete  = "media/%s/delete/"
+	urlMediaArchive = "media/%s/only_me/"
+	urlMediaEdit    = "media/%s/edit_media/"
+)
+
+// mediaTypeName returns media type as instagram expects it in requests, e.g. PHOTO.
+func mediaTypeName(t int) string {
+	return strings.ToUpper(goinsta.MediaToString(t))
+}
+
+// DeletePost deletes feed post, media type of the post is sent as instagram rejects delete without it.
+func (c *Client) DeletePost(ctx context.Context, post models.Post) error {
+	err := c.postMedia(ctx, deletePath(post.ID, post.MediaType), post.ID, map[string]any{"igtv_feed_preview": "false"})
+	if err != nil {
+		return fmt.Errorf("delete post: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStory deletes story, media type of the story is sent as instagram rejects delete without it.
+func (c *Client) DeleteStory(ctx context.Context, story models.Story) error {
+	waterfall, err := newUUID()
+	if err != nil {
+		return fmt.Errorf("delete story: %w", err)
+	}
+
+	err = c.postMedia(ctx, deletePath(story.ID, story.MediaType), story.ID, map[string]any{"deep_delete_waterfall": waterfall})
+	if err != nil {
+		return fmt.Errorf("delete story: %w", err)
+	}
+
+	return nil
+}
+
+func deletePath(id, mediaType string) string {
+	return fmt.Sprintf(urlMediaDelete, id) + "?" + url.Values{"media_type": {mediaType}}.Encode()
+}
+
+// ArchiveMedia hides feed post from the profile, it stays available in the archive.
+func (c *Client) ArchiveMedia(ctx context.Context, id string) error {
+	if err := c.postMedia(ctx, fmt.Sprintf(urlMediaArchive, id), id, nil); err != nil {
+		return fmt.Errorf("archive media: %w", err)
+	}
+
+	return nil
+}
+
+// EditCaption replaces caption of the feed post.
+func (c *Client) EditCaption(ctx context.Context, id, caption string) error {
+	if err := c.postMedia(ctx, fmt.Sprintf(urlMediaEdit, id), id, map[string]any{"caption_text": caption}); err != nil {
+		return fmt.Errorf("edit caption: %w", err)
+	}
+
+	return nil
+}
+
+// postMedia makes paced write request to the media endpoint, media id and account ids are added to data.
+func (c *Client) postMedia(ctx context.Context, endpoint, id string, data map[string]any) error {
+	cfg := c.client.ExportConfig()
+
+	body := map[string]any{
+		"media_id": id,
+		"_uid":     strconv.FormatInt(cfg.ID, 10),
+		"_uuid":    cfg.UUID,
+	}
+
+	for k, v := range data {
+		body[k] = v
+	}
+
+	return c.do(ctx, ratelimit.ClassWrite, func() error {
+		_, err := c.sendPrivate(ctx, http.MethodPost, endpoint, body)
+
+		return err
+	})
+}
+
+// newUUID returns random (version 4) UUID.
+func newUUID() (string, error) {
+	const (
+		size = 16
+		// version and variant bits of random UUID.
+		version, versionMask = 0x40, 0x0f
+		variant, variantMask = 0x80, 0x3f
+	)
+
+	b := make([]byte, size)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate uuid: %w", err)
+	}
+
+	b[6] = (b[6] & versionMask) | version
+	b[8] = (b[8] & variantMask) | variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,165 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// goinsta feed and comments iterators drop request errors, so these endpoints are requested directly.
+const (
+	urlUserFeed      = "feed/user/%d/"
+	urlMediaLikers   = "media/%s/likers/"
+	urlMediaComments = "media/%s/comments/"
+)
+
+type userResp struct {
+	ID       int64  `json:"pk"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+}
+
+func (u userResp) user() models.User {
+	return models.MakeUser(u.ID, u.Username, u.FullName)
+}
+
+type feedResp struct {
+	Items []struct {
+		ID           string `json:"id"`
+		Code         string `json:"code"`
+		TakenAt      int64  `json:"taken_at"`
+		LikeCount    int    `json:"like_count"`
+		CommentCount int    `json:"comment_count"`
+		MediaType    int    `json:"media_type"`
+		// caption is null for posts without caption.
+		Caption *struct {
+			Text string `json:"text"`
+		} `json:"caption"`
+	} `json:"items"`
+	MoreAvailable bool            `json:"more_available"`
+	NextMaxID     json.RawMessage `json:"next_max_id"`
+}
+
+type likersResp struct {
+	Users []userResp `json:"users"`
+}
+
+type commentsResp struct {
+	Comments []struct {
+		User userResp `json:"user"`
+	} `json:"comments"`
+	HasMoreComments bool            `json:"has_more_comments"`
+	NextMaxID       json.RawMessage `json:"next_max_id"`
+}
+
+// Posts returns the last posts of the account, newest first.
+func (c *Client) Posts(ctx context.Context, limit int) ([]models.Post, error) {
+	var (
+		posts []models.Post
+		maxID string
+	)
+
+	for len(posts) < limit {
+		var resp feedResp
+
+		if err := c.getPrivate(ctx, withMaxID(fmt.Sprintf(urlUserFeed, c.client.Account.ID), maxID), &resp); err != nil {
+			return nil, fmt.Errorf("posts: %w", err)
+		}
+
+		for _, it := range resp.Items {
+			post := models.Post{
+				ID:        it.ID,
+				Code:      it.Code,
+				TakenAt:   time.Unix(it.TakenAt, 0),
+				Likes:     it.LikeCount,
+				Comments:  it.CommentCount,
+				MediaType: mediaTypeName(it.MediaType),
+			}
+
+			if it.Caption != nil {
+				post.Caption = it.Caption.Text
+			}
+
+			posts = append(posts, post)
+		}
+
+		maxID = nextMaxID(resp.NextMaxID)
+		if !resp.MoreAvailable || maxID == "" {
+			break
+		}
+	}
+
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+
+	return posts, nil
+}
+
+// Likers returns users that liked the post.
+func (c *Client) Likers(ctx context.Context, post models.Post) ([]models.User, error) {
+	var resp likersResp
+
+	if err := c.getPrivate(ctx, fmt.Sprintf(urlMediaLikers, post.ID), &resp); err != nil {
+		return nil, fmt.Errorf("likers: %w", err)
+	}
+
+	users := make([]models.User, 0, len(resp.Users))
+
+	for _, u := range resp.Users {
+		users = append(users, u.user())
+	}
+
+	return users, nil
+}
+
+// Commenters returns authors of the post comments, user is returned once per comment.
+func (c *Client) Commenters(ctx context.Context, post models.Post) ([]models.User, error) {
+	var (
+		users []models.User
+		maxID string
+	)
+
+	for {
+		var resp commentsResp
+
+		if err := c.getPrivate(ctx, withMaxID(fmt.Sprintf(urlMediaComments, post.ID), maxID), &resp); err != nil {
+			return nil, fmt.Errorf("commenters: %w", err)
+		}
+
+		for _, cm := range resp.Comments {
+			users = append(users, cm.User.user())
+		}
+
+		maxID = nextMaxID(resp.NextMaxID)
+		if !resp.HasMoreComments || maxID == "" {
+			return users, nil
+		}
+	}
+}
+
+// getPrivate makes paced read request to the private API and decodes response.
+func (c *Client) getPrivate(ctx context.Context, endpoint string, v any) error {
+	return c.do(ctx, ratelimit.ClassRead, func() error {
+		body, err := c.sendPrivate(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(body, v)
+	})
+}
+
+func withMaxID(endpoint, maxID string) string {
+	if maxID == "" {
+		return endpoint
+	}
+
+	return endpoint + "?" + url.Values{"max_id": {maxID}}.Encode()
+}
@@ -0,0 +1,139 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Davincible/goinsta/v3"
+)
+
+// Private API constants, mirror ones used by goinsta, so requests look the same.
+const (
+	apiURL         = "https://i.instagram.com/api/v1/"
+	appID          = "567067343352427"
+	appVersion     = "250.0.0.21.109"
+	appVersionCode = "394071253"
+	appLocale      = "en_US"
+)
+
+// sendPrivate sends request to the private API endpoint that goinsta doesn't cover.
+// Request is authorized with session headers and sent by the same HTTP client as goinsta requests.
+// POST requests data is signed the same way as goinsta does. Errors are goinsta errors, so they could be classified.
+func (c *Client) sendPrivate(ctx context.Context, method, endpoint string, data any) ([]byte, error) {
+	cfg := c.client.ExportConfig()
+
+	u := apiURL + endpoint
+
+	var body io.Reader
+
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("marshal data: %w", err)
+		}
+
+		body = strings.NewReader(url.Values{"signed_body": {"SIGNATURE." + string(b)}}.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	for k, v := range cfg.HeaderOptions {
+		if v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	req.Header.Set("User-Agent", userAgent(cfg.Device))
+	req.Header.Set("Accept-Language", appLocale)
+	req.Header.Set("X-Ig-App-Id", appID)
+	req.Header.Set("X-Ig-App-Locale", appLocale)
+	req.Header.Set("X-Ig-Device-Id", cfg.UUID)
+	req.Header.Set("X-Ig-Android-Id", cfg.DeviceID)
+	req.Header.Set("X-Ig-Family-Device-Id", cfg.FamilyID)
+	req.Header.Set("Ig-Intended-User-Id", strconv.FormatInt(cfg.ID, 10))
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if err = responseError(resp.StatusCode, endpoint, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// responseError converts error response to goinsta error.
+func responseError(code int, endpoint string, body []byte) error {
+	switch {
+	case code >= http.StatusOK && code < http.StatusMultipleChoices:
+		return nil
+	case code == http.StatusBadRequest:
+		e := goinsta.Error400{Endpoint: endpoint, Code: code}
+
+		// response could be not a json, message is empty then.
+		_ = json.Unmarshal(body, &e)
+
+		// same sentinels as goinsta returns for its requests.
+		switch e.GetMessage() {
+		case "login_required":
+			return goinsta.ErrLoginRequired
+		case "checkpoint_required":
+			return goinsta.ErrCheckpointRequired
+		case "challenge_required", "checkpoint_challenge_required":
+			return goinsta.ErrChallengeRequired
+		}
+
+		return e
+	case code == http.StatusTooManyRequests:
+		return goinsta.ErrTooManyRequests
+	default:
+		e := goinsta.ErrorN{Endpoint: endpoint}
+
+		_ = json.Unmarshal(body, &e)
+
+		e.Status = strconv.Itoa(code)
+
+		return e
+	}
+}
+
+// userAgent builds the same User-Agent as goinsta for the device.
+func userAgent(d goinsta.Device) string {
+	return fmt.Sprintf("Instagram %s Android (%d/%d; %s; %s; %s; %s; %s; %s; %s; %s)",
+		appVersion,
+		d.AndroidVersion,
+		d.AndroidRelease,
+		d.ScreenDpi,
+		d.ScreenResolution,
+		d.Manufacturer,
+		d.Model,
+		d.CodeName,
+		d.Chipset,
+		appLocale,
+		appVersionCode,
+	)
+}
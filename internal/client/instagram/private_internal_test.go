@@ -0,0 +1,65 @@
+package instagram
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+)
+
+func Test_responseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		body    string
+		wantErr error
+	}{
+		{
+			name:    "ok",
+			code:    http.StatusOK,
+			body:    `{"status":"ok"}`,
+			wantErr: nil,
+		},
+		{
+			name:    "login required",
+			code:    http.StatusBadRequest,
+			body:    `{"message":"login_required","status":"fail"}`,
+			wantErr: clientErrors.ErrLoginRequired,
+		},
+		{
+			name:    "rate limited",
+			code:    http.StatusTooManyRequests,
+			body:    ``,
+			wantErr: clientErrors.ErrRateLimited,
+		},
+		{
+			name:    "server error",
+			code:    http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			wantErr: clientErrors.ErrTransient,
+		},
+		{
+			name:    "not found",
+			code:    http.StatusNotFound,
+			body:    `{"message":"Page not found","status":"fail"}`,
+			wantErr: clientErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(responseError(tt.code, "friendships/besties/", []byte(tt.body)))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
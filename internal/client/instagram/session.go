@@ -0,0 +1,380 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"path/filepath"
+
+	"github.com/Davincible/goinsta/v3"
+	log "github.com/obalunenko/logger"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/internal/session"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+func sessionFile(dir, uname string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.sess", uname))
+}
+
+// sessionKey returns passphrase for session encryption.
+// Key is taken from the environment, in other case it will be asked when encryption is enabled
+// or existing session file is encrypted. Empty key means that session is stored as plaintext.
+func sessionKey(sessFile string, encrypt bool) ([]byte, error) {
+	if k := os.Getenv(session.EnvKey); k != "" {
+		return []byte(k), nil
+	}
+
+	if !encrypt && !isSessionEncrypted(sessFile) {
+		return nil, nil
+	}
+
+	k, err := passphraseInput("What is your session passphrase?")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(k), nil
+}
+
+func isSessionEncrypted(sessFile string) bool {
+	content, err := os.ReadFile(filepath.Clean(sessFile))
+	if err != nil {
+		return false
+	}
+
+	return session.IsEncrypted(content)
+}
+
+// importSession reads goinsta session from file and applies HTTP transport settings.
+// It doesn't make any requests, account is not synced. Returns HTTP client used by goinsta.
+func importSession(sessFile string, sessKey []byte, hp transport.Params) (*goinsta.Instagram, *http.Client, error) {
+	content, err := session.Read(sessFile, sessKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cfg goinsta.ConfigFile
+
+	if err = json.Unmarshal(content, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", clientErrors.ErrInvalidSession, err)
+	}
+
+	if cfg.Account == nil {
+		cfg.Account = &goinsta.Account{
+			ID: cfg.ID,
+		}
+	}
+
+	insta, err := goinsta.ImportConfig(cfg, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import config: %w", err)
+	}
+
+	hc, err := applyTransport(insta, hp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return insta, hc, nil
+}
+
+// applyTransport sets HTTP client with configured transport for the goinsta client.
+// Returned client is shared with requests that goinsta doesn't cover.
+func applyTransport(insta *goinsta.Instagram, hp transport.Params) (*http.Client, error) {
+	rt, err := transport.New(hp)
+	if err != nil {
+		return nil, fmt.Errorf("http transport: %w", err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("cookie jar: %w", err)
+	}
+
+	hc := &http.Client{
+		Transport: rt,
+		Jar:       jar,
+		Timeout:   hp.Timeout,
+	}
+
+	insta.SetHTTPClient(hc)
+
+	return hc, nil
+}
+
+func exportSession(cli *goinsta.Instagram, sessFile string, sessKey []byte) error {
+	var buf bytes.Buffer
+
+	if err := cli.ExportIO(&buf); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	return session.Write(sessFile, sessKey, buf.Bytes())
+}
+
+// RotateSessionKey re-encrypts stored session with a new passphrase.
+// Current key is taken from the INSTADIFF_SESSION_KEY and new key from the INSTADIFF_SESSION_NEW_KEY
+// environment variables, when they are not set - passphrases will be asked.
+func RotateSessionKey(ctx context.Context, p Params) error {
+	uname, err := getUsername(p)
+	if err != nil {
+		return err
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	oldKey, err := sessionKey(sessFile, false)
+	if err != nil {
+		return fmt.Errorf("current session key: %w", err)
+	}
+
+	newKey := os.Getenv(session.EnvNewKey)
+
+	if newKey == "" {
+		newKey, err = passphraseInput("What is your new session passphrase?")
+		if err != nil {
+			return fmt.Errorf("new session key: %w", err)
+		}
+	}
+
+	if err = session.RotateKey(sessFile, oldKey, []byte(newKey)); err != nil {
+		return fmt.Errorf("rotate key: %w", err)
+	}
+
+	log.WithField(ctx, "session_file", sessFile).Info("Session key rotated")
+
+	return nil
+}
+
+// SessionUsername returns account username from the stored session, it is the username of logged-in client
+// and could differ from passed one, e.g. by case or when email is used for login.
+// Passed username is returned when there is no stored session. No requests are made.
+func SessionUsername(p Params) (string, error) {
+	uname, err := getUsername(p)
+	if err != nil {
+		return "", err
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	if _, err = os.Stat(sessFile); errors.Is(err, os.ErrNotExist) {
+		return uname, nil
+	}
+
+	sessKey, err := sessionKey(sessFile, false)
+	if err != nil {
+		return "", fmt.Errorf("session key: %w", err)
+	}
+
+	content, err := session.Read(sessFile, sessKey)
+	if err != nil {
+		return "", err
+	}
+
+	var cfg goinsta.ConfigFile
+
+	if err = json.Unmarshal(content, &cfg); err != nil {
+		return "", fmt.Errorf("%w: %v", clientErrors.ErrInvalidSession, err)
+	}
+
+	if cfg.Account != nil && cfg.Account.Username != "" {
+		return cfg.Account.Username, nil
+	}
+
+	return uname, nil
+}
+
+// SessionStatus returns details of the stored session and validates it by opening the app.
+func SessionStatus(ctx context.Context, p Params) (models.SessionInfo, error) {
+	uname, err := getUsername(p)
+	if err != nil {
+		return models.SessionInfo{}, err
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	fi, err := os.Stat(sessFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return models.SessionInfo{}, fmt.Errorf("%s: %w", sessFile, clientErrors.ErrNoSession)
+		}
+
+		return models.SessionInfo{}, fmt.Errorf("stat session file: %w", err)
+	}
+
+	sessKey, err := sessionKey(sessFile, false)
+	if err != nil {
+		return models.SessionInfo{}, fmt.Errorf("session key: %w", err)
+	}
+
+	info := models.SessionInfo{
+		Username:  uname,
+		FilePath:  sessFile,
+		UpdatedAt: fi.ModTime(),
+		Encrypted: isSessionEncrypted(sessFile),
+		Valid:     false,
+	}
+
+	insta, _, err := importSession(sessFile, sessKey, p.HTTP.ForAccount(uname))
+	if err != nil {
+		return info, fmt.Errorf("import session: %w", err)
+	}
+
+	if insta.Account != nil && insta.Account.Username != "" {
+		info.Username = insta.Account.Username
+	}
+
+	stop := spinner.Set("Validating session", "", "yellow")
+
+	err = insta.OpenApp()
+
+	stop()
+
+	if err != nil {
+		log.WithError(ctx, err).Warn("Session is not valid")
+
+		return info, nil
+	}
+
+	info.Valid = true
+
+	return info, nil
+}
+
+// LogoutSession sends logout request for the stored session and removes session file.
+// When session could not be imported - only session file is removed.
+func LogoutSession(ctx context.Context, p Params) error {
+	uname, err := getUsername(p)
+	if err != nil {
+		return err
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	if _, err = os.Stat(sessFile); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s: %w", sessFile, clientErrors.ErrNoSession)
+		}
+
+		return fmt.Errorf("stat session file: %w", err)
+	}
+
+	sessKey, err := sessionKey(sessFile, false)
+	if err != nil {
+		return fmt.Errorf("session key: %w", err)
+	}
+
+	insta, _, err := importSession(sessFile, sessKey, p.HTTP.ForAccount(uname))
+	if err != nil {
+		log.WithError(ctx, err).Warn("Failed to import session, only session file will be removed")
+
+		if err = os.Remove(sessFile); err != nil {
+			return fmt.Errorf("remove session file: %w", err)
+		}
+
+		log.WithField(ctx, "file_path", sessFile).Info("Session file removed")
+
+		return nil
+	}
+
+	c := Client{
+		client:   insta,
+		sessFile: sessFile,
+		sessKey:  sessKey,
+	}
+
+	return c.Logout(ctx)
+}
+
+// ExportSession copies stored session to the dst path to be moved to another machine.
+// Exported session is encrypted with the same key as the stored one.
+func ExportSession(ctx context.Context, p Params, dst string) error {
+	uname, err := getUsername(p)
+	if err != nil {
+		return err
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	sessKey, err := sessionKey(sessFile, false)
+	if err != nil {
+		return fmt.Errorf("session key: %w", err)
+	}
+
+	content, err := session.Read(sessFile, sessKey)
+	if err != nil {
+		return err
+	}
+
+	if err = session.Write(dst, sessKey, content); err != nil {
+		return err
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"session_file": sessFile,
+		"file_path":    dst,
+		"encrypted":    len(sessKey) != 0,
+	}).Info("Session exported")
+
+	return nil
+}
+
+// ImportSession stores session exported by ExportSession. Returns username of imported session.
+func ImportSession(ctx context.Context, p Params, src string) (string, error) {
+	srcKey, err := sessionKey(src, false)
+	if err != nil {
+		return "", fmt.Errorf("session key: %w", err)
+	}
+
+	content, err := session.Read(src, srcKey)
+	if err != nil {
+		return "", err
+	}
+
+	var cfg goinsta.ConfigFile
+
+	if err = json.Unmarshal(content, &cfg); err != nil {
+		return "", fmt.Errorf("%w: %v", clientErrors.ErrInvalidSession, err)
+	}
+
+	uname := cfg.User
+	if cfg.Account != nil && cfg.Account.Username != "" {
+		uname = cfg.Account.Username
+	}
+
+	if uname == "" {
+		return "", fmt.Errorf("%w: username is empty", clientErrors.ErrInvalidSession)
+	}
+
+	if p.Username != "" && p.Username != uname {
+		return "", fmt.Errorf("%w: session belongs to %s", clientErrors.ErrInvalidSession, uname)
+	}
+
+	sessFile := sessionFile(p.SessionPath, uname)
+
+	sessKey, err := sessionKey(sessFile, p.EncryptSession)
+	if err != nil {
+		return "", fmt.Errorf("session key: %w", err)
+	}
+
+	if err = session.Write(sessFile, sessKey, content); err != nil {
+		return "", err
+	}
+
+	log.WithFields(ctx, log.Fields{
+		"session_file": sessFile,
+		"username":     uname,
+		"encrypted":    len(sessKey) != 0,
+	}).Info("Session imported")
+
+	return uname, nil
+}
@@ -0,0 +1,119 @@
+package instagram
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/session"
+)
+
+func TestExportImportSession(t *testing.T) {
+	const uname = "tester"
+
+	content := []byte(`{"id":1,"username":"tester","account":{"username":"tester"}}`)
+
+	tests := []struct {
+		name      string
+		key       string
+		encrypted bool
+	}{
+		{
+			name:      "plaintext",
+			key:       "",
+			encrypted: false,
+		},
+		{
+			name:      "encrypted",
+			key:       "secret",
+			encrypted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(session.EnvKey, tt.key)
+
+			ctx := context.Background()
+
+			srcDir, dstDir := t.TempDir(), t.TempDir()
+
+			require.NoError(t, session.Write(sessionFile(srcDir, uname), []byte(tt.key), content))
+
+			exported := filepath.Join(t.TempDir(), "exported.sess")
+
+			require.NoError(t, ExportSession(ctx, Params{SessionPath: srcDir, Username: uname}, exported))
+			assert.Equal(t, tt.encrypted, isSessionEncrypted(exported))
+
+			got, err := ImportSession(ctx, Params{SessionPath: dstDir}, exported)
+			require.NoError(t, err)
+			assert.Equal(t, uname, got)
+
+			imported := sessionFile(dstDir, uname)
+			assert.Equal(t, tt.encrypted, isSessionEncrypted(imported))
+
+			gotContent, err := session.Read(imported, []byte(tt.key))
+			require.NoError(t, err)
+			assert.Equal(t, content, gotContent)
+		})
+	}
+}
+
+func TestImportSession_Invalid(t *testing.T) {
+	t.Setenv(session.EnvKey, "")
+
+	src := filepath.Join(t.TempDir(), "exported.sess")
+
+	require.NoError(t, os.WriteFile(src, []byte(`{"id":1}`), session.FilePerm))
+
+	_, err := ImportSession(context.Background(), Params{SessionPath: t.TempDir()}, src)
+	require.ErrorIs(t, err, clientErrors.ErrInvalidSession)
+
+	require.NoError(t, os.WriteFile(src, []byte(`{"id":1,"username":"tester"}`), session.FilePerm))
+
+	_, err = ImportSession(context.Background(), Params{SessionPath: t.TempDir(), Username: "other"}, src)
+	require.ErrorIs(t, err, clientErrors.ErrInvalidSession)
+}
+
+func TestSessionUsername(t *testing.T) {
+	t.Setenv(session.EnvKey, "")
+
+	dir := t.TempDir()
+
+	require.NoError(t, session.Write(sessionFile(dir, "Tester"), nil,
+		[]byte(`{"id":1,"username":"Tester","account":{"username":"tester"}}`)))
+
+	tests := []struct {
+		name  string
+		uname string
+		want  string
+	}{
+		{
+			name:  "stored session",
+			uname: "Tester",
+			want:  "tester",
+		},
+		{
+			name:  "no session",
+			uname: "Other",
+			want:  "Other",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SessionUsername(Params{SessionPath: dir, Username: tt.uname})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+const (
+	urlUserStories  = "feed/user/%d/story/"
+	urlStoryViewers = "media/%s/list_reel_media_viewer/"
+)
+
+type storiesResp struct {
+	Reel *struct {
+		Items []struct {
+			ID          string `json:"id"`
+			TakenAt     int64  `json:"taken_at"`
+			ExpiringAt  int64  `json:"expiring_at"`
+			ViewerCount int    `json:"viewer_count"`
+			MediaType   int    `json:"media_type"`
+		} `json:"items"`
+	} `json:"reel"`
+}
+
+type storyViewersResp struct {
+	Users     []userResp      `json:"users"`
+	NextMaxID json.RawMessage `json:"next_max_id"`
+}
+
+// Stories returns active stories of the account, oldest first.
+func (c *Client) Stories(ctx context.Context) ([]models.Story, error) {
+	var resp storiesResp
+
+	if err := c.getPrivate(ctx, fmt.Sprintf(urlUserStories, c.client.Account.ID), &resp); err != nil {
+		return nil, fmt.Errorf("stories: %w", err)
+	}
+
+	// reel is null when there are no active stories.
+	if resp.Reel == nil {
+		return nil, nil
+	}
+
+	stories := make([]models.Story, 0, len(resp.Reel.Items))
+
+	for _, it := range resp.Reel.Items {
+		stories = append(stories, models.Story{
+			ID:        it.ID,
+			TakenAt:   time.Unix(it.TakenAt, 0),
+			ExpiresAt: time.Unix(it.ExpiringAt, 0),
+			Views:     it.ViewerCount,
+			MediaType: mediaTypeName(it.MediaType),
+		})
+	}
+
+	return stories, nil
+}
+
+// StoryViewers returns users that viewed the active story.
+func (c *Client) StoryViewers(ctx context.Context, story models.Story) ([]models.User, error) {
+	var (
+		users []models.User
+		maxID string
+	)
+
+	for {
+		var resp storyViewersResp
+
+		if err := c.getPrivate(ctx, withMaxID(fmt.Sprintf(urlStoryViewers, story.ID), maxID), &resp); err != nil {
+			return nil, fmt.Errorf("story viewers: %w", err)
+		}
+
+		for _, u := range resp.Users {
+			users = append(users, u.user())
+		}
+
+		maxID = nextMaxID(resp.NextMaxID)
+		if maxID == "" {
+			return users, nil
+		}
+	}
+}
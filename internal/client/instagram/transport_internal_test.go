@@ -0,0 +1,108 @@
+package instagram
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
+	"github.com/obalunenko/instadiff-cli/internal/session"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func writeTestSession(t *testing.T) string {
+	t.Helper()
+
+	t.Setenv(session.EnvKey, "")
+
+	sessFile := sessionFile(t.TempDir(), "tester")
+
+	content := []byte(`{"id":1,"username":"tester","xmid_expiry":-1,"account":{"pk":1,"username":"tester"}}`)
+
+	require.NoError(t, session.Write(sessFile, nil, content))
+
+	return sessFile
+}
+
+func TestImportSession_CustomTransport(t *testing.T) {
+	sessFile := writeTestSession(t)
+
+	var paths []string
+
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"user":{"pk":42,"username":"john"},"status":"ok"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	insta, hc, err := importSession(sessFile, nil, transport.Params{RoundTripper: rt})
+	require.NoError(t, err)
+
+	c := Client{
+		client:   insta,
+		sessFile: sessFile,
+		http:     hc,
+	}
+
+	u, err := c.profileByName(context.Background(), "john")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), u.ID)
+	assert.Equal(t, "john", u.Username)
+	require.NotEmpty(t, paths)
+	assert.Equal(t, "/api/v1/users/john/usernameinfo/", paths[len(paths)-1])
+}
+
+func TestImportSession_TransportSettings(t *testing.T) {
+	sessFile := writeTestSession(t)
+
+	_, hc, err := importSession(sessFile, nil, transport.Params{
+		Proxy:                 "socks5://127.0.0.1:1080",
+		Timeout:               time.Minute,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MinTLSVersion:         tls.VersionTLS13,
+		InsecureSkipVerify:    true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Minute, hc.Timeout)
+
+	tr, ok := hc.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	req, err := http.NewRequest(http.MethodGet, "https://i.instagram.com/api/v1/", http.NoBody)
+	require.NoError(t, err)
+
+	proxy, err := tr.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "socks5://127.0.0.1:1080", proxy.String())
+
+	assert.Equal(t, 10*time.Second, tr.ResponseHeaderTimeout)
+	require.NotNil(t, tr.TLSClientConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), tr.TLSClientConfig.MinVersion)
+	assert.True(t, tr.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestImportSession_InvalidProxy(t *testing.T) {
+	sessFile := writeTestSession(t)
+
+	_, _, err := importSession(sessFile, nil, transport.Params{Proxy: "ftp://127.0.0.1:21"})
+	require.ErrorIs(t, err, transport.ErrUnsupportedProxyScheme)
+}
@@ -0,0 +1,8 @@
+package ratelimit
+
+import (
+	"errors"
+)
+
+// ErrInvalidClass returned when class of request is not supported.
+var ErrInvalidClass = errors.New("invalid request class")
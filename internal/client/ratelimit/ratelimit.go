@@ -0,0 +1,236 @@
+// Package ratelimit implements adaptive pacing of client requests.
+//
+// Requests are split into classes (reads and writes) and each class has own token bucket.
+// Every request waits for a token and additional random delay, so requests don't look like a bot.
+// When social network responds with rate limit error, all buckets are slowed down and recover
+// gradually with successful responses.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+)
+
+//go:generate stringer -type=Class -trimprefix=Class,class -linecomment
+
+// Class represents class of endpoints that share the same limits.
+type Class uint
+
+const (
+	// ClassUndefined represents undefined class.
+	ClassUndefined Class = iota // undefined
+
+	// ClassRead represents requests that fetch data: lists, profiles.
+	ClassRead // read
+	// ClassWrite represents requests that change data: follow, unfollow, block, upload.
+	ClassWrite // write
+
+	// classSentinel should be always last, marks boundary of valid values.
+	classSentinel // sentinel
+)
+
+// Valid checks if Class value is in valid boundaries.
+func (c Class) Valid() bool {
+	return c > ClassUndefined && c < classSentinel
+}
+
+// Rule describes limits for the class of requests.
+type Rule struct {
+	// PerMinute is a number of requests allowed per minute.
+	PerMinute float64
+	// Burst is a number of requests that could be done without waiting.
+	Burst int
+	// Jitter is a max random delay added to each request.
+	Jitter time.Duration
+}
+
+// Default rules.
+var (
+	DefaultReadRule = Rule{
+		PerMinute: 30,
+		Burst:     5,
+		Jitter:    2 * time.Second,
+	}
+
+	DefaultWriteRule = Rule{
+		PerMinute: 6,
+		Burst:     1,
+		Jitter:    5 * time.Second,
+	}
+)
+
+const (
+	// slowdownFactor multiplies delays on each rate limit response.
+	slowdownFactor = 2
+	// maxSlowdown caps how much slower than configured the requests could be.
+	maxSlowdown = 16
+	// recoverFactor decreases slowdown on each successful response.
+	recoverFactor = 0.9
+)
+
+// Params holds Limiter constructor parameters. Not set rules are replaced with defaults.
+type Params struct {
+	Read  Rule
+	Write Rule
+}
+
+// Limiter paces requests. It is safe for concurrent use and should be shared by all requests of one account.
+type Limiter struct {
+	mu       sync.Mutex
+	buckets  map[Class]*bucket
+	slowdown float64
+	now      func() time.Time
+}
+
+type bucket struct {
+	rule   Rule
+	tokens float64
+	last   time.Time
+}
+
+// New creates Limiter.
+func New(p Params) *Limiter {
+	now := time.Now
+
+	return &Limiter{
+		buckets: map[Class]*bucket{
+			ClassRead:  newBucket(withDefaults(p.Read, DefaultReadRule), now()),
+			ClassWrite: newBucket(withDefaults(p.Write, DefaultWriteRule), now()),
+		},
+		slowdown: 1,
+		now:      now,
+	}
+}
+
+func withDefaults(r, d Rule) Rule {
+	if r.PerMinute <= 0 {
+		r.PerMinute = d.PerMinute
+	}
+
+	if r.Burst <= 0 {
+		r.Burst = d.Burst
+	}
+
+	if r.Jitter < 0 {
+		r.Jitter = 0
+	}
+
+	return r
+}
+
+func newBucket(r Rule, now time.Time) *bucket {
+	return &bucket{
+		rule:   r,
+		tokens: float64(r.Burst),
+		last:   now,
+	}
+}
+
+// Wait blocks until request of passed class is allowed or context is done.
+// Nil Limiter doesn't limit anything.
+func (l *Limiter) Wait(ctx context.Context, c Class) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	d, err := l.reserve(c)
+	if err != nil {
+		return err
+	}
+
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reserve takes token from the bucket and returns how long to wait before request.
+func (l *Limiter) reserve(c Class) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[c]
+	if !ok {
+		return 0, ErrInvalidClass
+	}
+
+	now := l.now()
+
+	// tokens per second considering slowdown.
+	rate := b.rule.PerMinute / float64(time.Minute/time.Second) / l.slowdown
+
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if burst := float64(b.rule.Burst); b.tokens > burst {
+		b.tokens = burst
+	}
+
+	b.last = now
+
+	b.tokens--
+
+	var d time.Duration
+
+	if b.tokens < 0 {
+		d = time.Duration(-b.tokens / rate * float64(time.Second))
+	}
+
+	return d + jitter(b.rule.Jitter), nil
+}
+
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+
+	return rand.N(limit) //nolint:gosec // jitter doesn't need crypto random.
+}
+
+// Observe adapts pacing to the request result: rate limit error slows all requests down,
+// successful responses gradually restore configured pace.
+func (l *Limiter) Observe(err error) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case errors.Is(err, clientErrors.ErrRateLimited):
+		l.slowdown *= slowdownFactor
+		if l.slowdown > maxSlowdown {
+			l.slowdown = maxSlowdown
+		}
+	case err == nil:
+		l.slowdown *= recoverFactor
+		if l.slowdown < 1 {
+			l.slowdown = 1
+		}
+	}
+}
+
+// Slowdown returns current slowdown multiplier, 1 means configured pace.
+func (l *Limiter) Slowdown() float64 {
+	if l == nil {
+		return 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.slowdown
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time {
+	return f.t
+}
+
+func (f *fakeClock) add(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func newTestLimiter(tb testing.TB) (*Limiter, *fakeClock) {
+	tb.Helper()
+
+	clock := &fakeClock{t: time.Now()}
+
+	l := New(Params{
+		Read: Rule{
+			PerMinute: 60,
+			Burst:     2,
+			Jitter:    0,
+		},
+		Write: Rule{
+			PerMinute: 6,
+			Burst:     1,
+			Jitter:    0,
+		},
+	})
+
+	l.now = clock.now
+
+	for _, b := range l.buckets {
+		b.last = clock.t
+	}
+
+	return l, clock
+}
+
+func TestLimiter_reserve(t *testing.T) {
+	l, clock := newTestLimiter(t)
+
+	// burst is available immediately.
+	for i := 0; i < 2; i++ {
+		d, err := l.reserve(ClassRead)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), d)
+	}
+
+	// next read should wait for one token: 1 request per second.
+	d, err := l.reserve(ClassRead)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+
+	// classes have separate buckets.
+	d, err = l.reserve(ClassWrite)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, err = l.reserve(ClassWrite)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, d)
+
+	// tokens are refilled with time, but not more than burst.
+	clock.add(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		d, err = l.reserve(ClassRead)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), d)
+	}
+
+	_, err = l.reserve(ClassUndefined)
+	require.ErrorIs(t, err, ErrInvalidClass)
+}
+
+func TestLimiter_Observe(t *testing.T) {
+	l, _ := newTestLimiter(t)
+
+	errRateLimited := fmt.Errorf("%w: wait", clientErrors.ErrRateLimited)
+
+	l.Observe(errRateLimited)
+	assert.InDelta(t, 2, l.Slowdown(), 0.001)
+
+	// rate limited requests are paced slower.
+	_, err := l.reserve(ClassWrite)
+	require.NoError(t, err)
+
+	d, err := l.reserve(ClassWrite)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, d)
+
+	for i := 0; i < 10; i++ {
+		l.Observe(errRateLimited)
+	}
+
+	assert.InDelta(t, maxSlowdown, l.Slowdown(), 0.001)
+
+	// other errors doesn't change pace.
+	l.Observe(clientErrors.ErrTransient)
+	assert.InDelta(t, maxSlowdown, l.Slowdown(), 0.001)
+
+	for i := 0; i < 100; i++ {
+		l.Observe(nil)
+	}
+
+	assert.InDelta(t, 1, l.Slowdown(), 0.001)
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	var l *Limiter
+
+	require.NoError(t, l.Wait(context.Background(), ClassRead))
+
+	l, _ = newTestLimiter(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, l.Wait(ctx, ClassWrite), context.Canceled)
+}
@@ -0,0 +1,138 @@
+// Package retry implements retry policy with exponential backoff and jitter for client requests.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+)
+
+// Default policy values.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultBaseDelay      = 2 * time.Second
+	DefaultRateLimitDelay = 30 * time.Second
+	DefaultMaxDelay       = 5 * time.Minute
+)
+
+// Policy describes how failed requests are retried.
+// Only retryable errors (rate limited and transient) are retried.
+type Policy struct {
+	// MaxAttempts is a total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is an initial backoff for transient errors.
+	BaseDelay time.Duration
+	// RateLimitDelay is an initial backoff for rate limit errors.
+	RateLimitDelay time.Duration
+	// MaxDelay caps backoff duration.
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy returns Policy with default values.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    DefaultMaxAttempts,
+		BaseDelay:      DefaultBaseDelay,
+		RateLimitDelay: DefaultRateLimitDelay,
+		MaxDelay:       DefaultMaxDelay,
+	}
+}
+
+// withDefaults fills not set values with defaults.
+func (p Policy) withDefaults() Policy {
+	d := DefaultPolicy()
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = d.BaseDelay
+	}
+
+	if p.RateLimitDelay <= 0 {
+		p.RateLimitDelay = d.RateLimitDelay
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = d.MaxDelay
+	}
+
+	return p
+}
+
+// Do calls f until it succeeds, returns not retryable error or attempts are exhausted.
+// Returns last error.
+func (p Policy) Do(ctx context.Context, f func() error) error {
+	p = p.withDefaults()
+
+	var err error
+
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = f()
+		if err == nil || !clientErrors.IsRetryable(err) || attempt == p.MaxAttempts {
+			return err
+		}
+
+		delay := p.Delay(attempt, err)
+
+		log.WithError(ctx, err).WithFields(log.Fields{
+			"attempt": attempt,
+			"delay":   delay.String(),
+		}).Warn("Request failed, retrying")
+
+		if err = sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Delay returns backoff before the next attempt after attempt-th one failed with err.
+// Backoff grows exponentially and has equal jitter: it is randomized in [d/2, d).
+func (p Policy) Delay(attempt int, err error) time.Duration {
+	p = p.withDefaults()
+
+	d := p.BaseDelay
+	if errors.Is(err, clientErrors.ErrRateLimited) {
+		d = p.RateLimitDelay
+	}
+
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	half := d / 2
+
+	if half <= 0 {
+		return d
+	}
+
+	return half + rand.N(half) //nolint:gosec // jitter doesn't need crypto random.
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+)
+
+func testPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:    3,
+		BaseDelay:      time.Millisecond,
+		RateLimitDelay: 2 * time.Millisecond,
+		MaxDelay:       10 * time.Millisecond,
+	}
+}
+
+func TestPolicy_Do(t *testing.T) {
+	errUnknown := errors.New("unknown")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantErr:   nil,
+		},
+		{
+			name:      "transient then success",
+			errs:      []error{clientErrors.ErrTransient, nil},
+			wantCalls: 2,
+			wantErr:   nil,
+		},
+		{
+			name: "rate limited all attempts",
+			errs: []error{
+				fmt.Errorf("%w: wait", clientErrors.ErrRateLimited),
+				fmt.Errorf("%w: wait", clientErrors.ErrRateLimited),
+				fmt.Errorf("%w: wait", clientErrors.ErrRateLimited),
+				nil,
+			},
+			wantCalls: 3,
+			wantErr:   clientErrors.ErrRateLimited,
+		},
+		{
+			name:      "not retryable",
+			errs:      []error{errUnknown, nil},
+			wantCalls: 1,
+			wantErr:   errUnknown,
+		},
+		{
+			name:      "login required is not retried",
+			errs:      []error{clientErrors.ErrLoginRequired, nil},
+			wantCalls: 1,
+			wantErr:   clientErrors.ErrLoginRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+
+			err := testPolicy().Do(context.Background(), func() error {
+				err := tt.errs[calls]
+
+				calls++
+
+				return err
+			})
+
+			assert.Equal(t, tt.wantCalls, calls)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicy_Do_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := testPolicy()
+	p.BaseDelay = time.Hour
+	p.MaxDelay = time.Hour
+
+	var calls int
+
+	err := p.Do(ctx, func() error {
+		calls++
+
+		cancel()
+
+		return clientErrors.ErrTransient
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	p := retry.Policy{
+		MaxAttempts:    5,
+		BaseDelay:      time.Second,
+		RateLimitDelay: time.Minute,
+		MaxDelay:       3 * time.Minute,
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{
+			name:    "first transient",
+			attempt: 1,
+			err:     clientErrors.ErrTransient,
+			want:    time.Second,
+		},
+		{
+			name:    "third transient",
+			attempt: 3,
+			err:     clientErrors.ErrTransient,
+			want:    4 * time.Second,
+		},
+		{
+			name:    "rate limited",
+			attempt: 2,
+			err:     clientErrors.ErrRateLimited,
+			want:    2 * time.Minute,
+		},
+		{
+			name:    "capped",
+			attempt: 4,
+			err:     clientErrors.ErrRateLimited,
+			want:    3 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Delay(tt.attempt, tt.err)
+
+			assert.GreaterOrEqual(t, got, tt.want/2)
+			assert.Less(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,420 @@
+// Package cassette records HTTP exchanges into fixture files and replays them,
+// so clients of social networks could be tested offline.
+//
+// Only data needed for replay is recorded: request method, URL and query, response status,
+// content type and body. Request headers and bodies are never stored, sensitive query parameters
+// and response body fields are scrubbed.
+package cassette
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces scrubbed values.
+const Redacted = "REDACTED"
+
+// sensitive holds lowercase names of query parameters and JSON fields that are scrubbed.
+var sensitive = map[string]bool{
+	"password":          true,
+	"enc_password":      true,
+	"token":             true,
+	"authorization":     true,
+	"sessionid":         true,
+	"session_id":        true,
+	"csrftoken":         true,
+	"_csrftoken":        true,
+	"device_id":         true,
+	"android_device_id": true,
+	"custom_device_id":  true,
+	"phone_id":          true,
+	"family_device_id":  true,
+	"guid":              true,
+	"_uuid":             true,
+	"uuid":              true,
+	"adid":              true,
+	"rank_token":        true,
+	"totp_seed":         true,
+	"two_factor_token":  true,
+	"verification_code": true,
+	"phone_number":      true,
+	"email":             true,
+	"fb_access_token":   true,
+	"nonce":             true,
+	"challenge_context": true,
+	"two_factor_info":   true,
+}
+
+// volatilePaths holds URL path prefixes followed by random names, they are recorded as glob.
+var volatilePaths = []string{
+	"/rupload_igphoto/",
+	"/rupload_igvideo/",
+}
+
+// Cassette is a list of recorded HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded HTTP exchange.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string `json:"method"`
+	// URL is request URL without query. On replay it is matched as a glob pattern (see path.Match),
+	// so random path segments could be replaced with '*'.
+	URL string `json:"url"`
+	// Query holds query parameters, on replay request should have the same values except redacted ones.
+	Query map[string]string `json:"query,omitempty"`
+}
+
+func (r Request) matches(req *http.Request) bool {
+	if r.Method != req.Method {
+		return false
+	}
+
+	if ok, err := path.Match(r.URL, requestURL(req.URL)); err != nil || !ok {
+		return false
+	}
+
+	q := req.URL.Query()
+
+	for k, v := range r.Query {
+		if v == Redacted {
+			continue
+		}
+
+		if q.Get(k) != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	// Body is stored as is when it is JSON and as JSON string in other case.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Load reads cassette from the file.
+func Load(path string) (*Cassette, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+
+	var c Cassette
+
+	if err = json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("decode cassette: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Save writes cassette to the file.
+func (c *Cassette) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+
+	const perm = 0o600
+
+	if err = os.WriteFile(path, append(content, '\n'), perm); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+
+	return nil
+}
+
+func requestURL(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+func makeRequest(req *http.Request) Request {
+	u := requestURL(req.URL)
+
+	for _, p := range volatilePaths {
+		if i := strings.Index(u, p); i >= 0 {
+			u = u[:i+len(p)] + "*"
+
+			break
+		}
+	}
+
+	r := Request{
+		Method: req.Method,
+		URL:    u,
+		Query:  nil,
+	}
+
+	q := req.URL.Query()
+	if len(q) == 0 {
+		return r
+	}
+
+	r.Query = make(map[string]string, len(q))
+
+	for k := range q {
+		v := q.Get(k)
+		if sensitive[strings.ToLower(k)] && v != "" {
+			v = Redacted
+		}
+
+		r.Query[k] = v
+	}
+
+	return r
+}
+
+func makeResponse(status int, contentType string, body []byte) Response {
+	return Response{
+		Status:      status,
+		ContentType: contentType,
+		Body:        encodeBody(body),
+	}
+}
+
+func encodeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var v interface{}
+
+	if err := json.Unmarshal(body, &v); err == nil {
+		scrub(v)
+
+		if b, err := json.Marshal(v); err == nil {
+			return b
+		}
+	}
+
+	b, err := json.Marshal(string(body))
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
+
+func decodeBody(raw json.RawMessage) []byte {
+	var s string
+
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s)
+	}
+
+	return raw
+}
+
+// scrub replaces sensitive JSON fields values.
+func scrub(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if sensitive[strings.ToLower(k)] {
+				t[k] = Redacted
+
+				continue
+			}
+
+			scrub(val)
+		}
+	case []interface{}:
+		for i := range t {
+			scrub(t[i])
+		}
+	}
+}
+
+// Recorder is a http.RoundTripper that records exchanges made with the underlying transport.
+// Cassette file is rewritten after each exchange, so it is kept even when the process is interrupted.
+type Recorder struct {
+	next http.RoundTripper
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates Recorder that saves exchanges made by next into the file at path.
+func NewRecorder(next http.RoundTripper, path string) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{
+		next:     next,
+		path:     path,
+		mu:       sync.Mutex{},
+		cassette: Cassette{},
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("record response: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  makeRequest(req),
+		Response: makeResponse(resp.StatusCode, resp.Header.Get("Content-Type"), body),
+	})
+
+	if err = r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// readBody reads and decompresses response body and replaces it with the plain one.
+func readBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = resp.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(body))
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// Replayer is a http.RoundTripper that serves responses from the cassette.
+// Requests are matched by method, URL and query, matching interactions are served in recorded order.
+// Request that doesn't match any of not served interactions fails with ErrNoInteraction.
+type Replayer struct {
+	mu     sync.Mutex
+	left   []Interaction
+	served []Request
+}
+
+// NewReplayer creates Replayer.
+func NewReplayer(c *Cassette) *Replayer {
+	left := make([]Interaction, len(c.Interactions))
+	copy(left, c.Interactions)
+
+	return &Replayer{
+		mu:     sync.Mutex{},
+		left:   left,
+		served: nil,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// drain body as real transport does, it could be a pipe.
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+
+		if err := req.Body.Close(); err != nil {
+			return nil, fmt.Errorf("close request body: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.left {
+		itr := r.left[i]
+
+		if !itr.Request.matches(req) {
+			continue
+		}
+
+		r.left = append(r.left[:i], r.left[i+1:]...)
+		r.served = append(r.served, makeRequest(req))
+
+		body := decodeBody(itr.Response.Body)
+
+		h := make(http.Header)
+		if itr.Response.ContentType != "" {
+			h.Set("Content-Type", itr.Response.ContentType)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", itr.Response.Status, http.StatusText(itr.Response.Status)),
+			StatusCode:    itr.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        h,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.String(), ErrNoInteraction)
+}
+
+// Served returns requests served by Replayer.
+func (r *Replayer) Served() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	served := make([]Request, len(r.served))
+	copy(served, r.served)
+
+	return served
+}
+
+// Left returns number of interactions that were not served yet.
+func (r *Replayer) Left() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.left)
+}
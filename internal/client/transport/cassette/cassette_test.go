@@ -0,0 +1,207 @@
+package cassette_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/transport/cassette"
+)
+
+func TestRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Ig-Set-Authorization", "Bearer secret")
+
+		body := `{"users":[{"pk":1,"username":"john"}],"token":"secret","status":"ok"}`
+
+		if r.URL.Query().Get("max_id") != "" {
+			body = `{"users":[{"pk":2,"username":"jane"}],"status":"ok"}`
+		}
+
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+
+			zw := gzip.NewWriter(w)
+			defer func() {
+				require.NoError(t, zw.Close())
+			}()
+
+			_, err := io.WriteString(zw, body)
+			require.NoError(t, err)
+
+			return
+		}
+
+		_, err := io.WriteString(w, body)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec := cassette.NewRecorder(http.DefaultTransport, path)
+
+	get := func(rt http.RoundTripper, query string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/users/?"+query, http.NoBody)
+		require.NoError(t, err)
+
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get(rec, "rank_token=abc")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"john"`)
+
+	status, body = get(rec, "rank_token=abc&max_id=next")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"jane"`)
+
+	c, err := cassette.Load(path)
+	require.NoError(t, err)
+	require.Len(t, c.Interactions, 2)
+
+	first := c.Interactions[0]
+	assert.Equal(t, cassette.Redacted, first.Request.Query["rank_token"])
+	assert.NotContains(t, string(first.Response.Body), "secret")
+	assert.Equal(t, "application/json", first.Response.ContentType)
+
+	rp := cassette.NewReplayer(c)
+
+	status, body = get(rp, "rank_token=other")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"john"`)
+
+	status, body = get(rp, "rank_token=other&max_id=next")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"jane"`)
+
+	assert.Equal(t, 0, rp.Left())
+	assert.Len(t, rp.Served(), 2)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/users/", http.NoBody)
+	require.NoError(t, err)
+
+	_, err = rp.RoundTrip(req) //nolint:bodyclose // error expected.
+	require.ErrorIs(t, err, cassette.ErrNoInteraction)
+}
+
+func TestReplayer_Match(t *testing.T) {
+	c := &cassette.Cassette{
+		Interactions: []cassette.Interaction{
+			{
+				Request: cassette.Request{
+					Method: http.MethodPost,
+					URL:    "https://i.instagram.com/rupload_igphoto/*",
+					Query:  nil,
+				},
+				Response: cassette.Response{
+					Status:      http.StatusOK,
+					ContentType: "text/plain",
+					Body:        []byte(`"uploaded"`),
+				},
+			},
+			{
+				Request: cassette.Request{
+					Method: http.MethodGet,
+					URL:    "https://i.instagram.com/api/v1/feed/",
+					Query: map[string]string{
+						"max_id": "2",
+					},
+				},
+				Response: cassette.Response{
+					Status:      http.StatusOK,
+					ContentType: "application/json",
+					Body:        []byte(`{"page":2}`),
+				},
+			},
+		},
+	}
+
+	rp := cassette.NewReplayer(c)
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		wantBody string
+		wantErr  error
+	}{
+		{
+			name:     "glob url",
+			method:   http.MethodPost,
+			url:      "https://i.instagram.com/rupload_igphoto/123_0_456",
+			wantBody: "uploaded",
+			wantErr:  nil,
+		},
+		{
+			name:     "query mismatch",
+			method:   http.MethodGet,
+			url:      "https://i.instagram.com/api/v1/feed/?max_id=1",
+			wantBody: "",
+			wantErr:  cassette.ErrNoInteraction,
+		},
+		{
+			name:     "query match",
+			method:   http.MethodGet,
+			url:      "https://i.instagram.com/api/v1/feed/?max_id=2",
+			wantBody: `{"page":2}`,
+			wantErr:  nil,
+		},
+		{
+			name:     "already served",
+			method:   http.MethodGet,
+			url:      "https://i.instagram.com/api/v1/feed/?max_id=2",
+			wantBody: "",
+			wantErr:  cassette.ErrNoInteraction,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, bytes.NewReader([]byte("payload")))
+			require.NoError(t, err)
+
+			resp, err := rp.RoundTrip(req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			defer func() {
+				require.NoError(t, resp.Body.Close())
+			}()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(string(body)))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package cassette
+
+import (
+	"errors"
+)
+
+// ErrNoInteraction returned by Replayer when request doesn't match any recorded interaction.
+var ErrNoInteraction = errors.New("no recorded interaction")
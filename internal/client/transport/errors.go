@@ -0,0 +1,14 @@
+package transport
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnsupportedProxyScheme returned when proxy URL scheme is not supported.
+	ErrUnsupportedProxyScheme = errors.New("unsupported proxy scheme")
+	// ErrInvalidProxy returned when proxy URL is not valid.
+	ErrInvalidProxy = errors.New("invalid proxy")
+	// ErrUnsupportedTLSVersion returned when TLS version could not be parsed.
+	ErrUnsupportedTLSVersion = errors.New("unsupported TLS version")
+)
@@ -0,0 +1,154 @@
+// Package transport builds HTTP transport for the client requests: proxy, timeouts and TLS settings.
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/client/transport/cassette"
+)
+
+// EnvRecord is an environment variable that holds path to the file for recording HTTP exchanges.
+const EnvRecord = "INSTADIFF_HTTP_RECORD"
+
+// Params holds HTTP transport settings.
+type Params struct {
+	// Proxy is a proxy URL, supported schemes: http, https, socks5 and socks5h.
+	// When empty, proxy from environment (HTTP_PROXY, HTTPS_PROXY) is used.
+	Proxy string
+	// AccountProxies holds proxies per account username, they override Proxy.
+	AccountProxies map[string]string
+	// Timeout limits the whole request time, including reading the response body. Zero means no timeout.
+	Timeout time.Duration
+	// DialTimeout limits time to establish connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout limits time to wait for a TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout limits time to wait for a response headers after request is written.
+	ResponseHeaderTimeout time.Duration
+	// MinTLSVersion is a minimum TLS version, zero means Go default.
+	MinTLSVersion uint16
+	// InsecureSkipVerify disables server certificate verification. Use it only for debugging with MITM proxies.
+	InsecureSkipVerify bool
+	// RoundTripper is a custom transport. When set, it is used as is and other settings except Timeout are ignored.
+	// Could be used to route requests to the local mock server in tests.
+	RoundTripper http.RoundTripper
+	// RecordPath is a path to the cassette file, when set all HTTP exchanges are recorded there
+	// with credentials scrubbed. Recorded cassettes are used as test fixtures.
+	RecordPath string
+}
+
+// ForAccount returns Params with proxy configured for the account.
+func (p Params) ForAccount(username string) Params {
+	if proxy, ok := p.AccountProxies[strings.ToLower(username)]; ok {
+		p.Proxy = proxy
+	}
+
+	return p
+}
+
+// New builds transport from the params.
+func New(p Params) (http.RoundTripper, error) {
+	rt, err := newTransport(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.RecordPath != "" {
+		rt = cassette.NewRecorder(rt, p.RecordPath)
+	}
+
+	return rt, nil
+}
+
+func newTransport(p Params) (http.RoundTripper, error) {
+	if p.RoundTripper != nil {
+		return p.RoundTripper, nil
+	}
+
+	proxy, err := proxyFunc(p.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport type %T", http.DefaultTransport)
+	}
+
+	t = t.Clone()
+
+	t.Proxy = proxy
+
+	if p.DialTimeout > 0 {
+		const keepAlive = 30 * time.Second
+
+		t.DialContext = (&net.Dialer{
+			Timeout:   p.DialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext
+	}
+
+	if p.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = p.TLSHandshakeTimeout
+	}
+
+	if p.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = p.ResponseHeaderTimeout
+	}
+
+	if p.MinTLSVersion != 0 || p.InsecureSkipVerify {
+		t.TLSClientConfig = &tls.Config{
+			MinVersion:         p.MinTLSVersion,
+			InsecureSkipVerify: p.InsecureSkipVerify, //nolint:gosec // explicitly enabled by user.
+		}
+	}
+
+	return t, nil
+}
+
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%s: %w", u.Scheme, ErrUnsupportedProxyScheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy host is empty: %w", ErrInvalidProxy)
+	}
+
+	return http.ProxyURL(u), nil
+}
+
+// ParseTLSVersion parses TLS version in format "1.2".
+func ParseTLSVersion(v string) (uint16, error) {
+	switch strings.TrimSpace(v) {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("%s: %w", v, ErrUnsupportedTLSVersion)
+	}
+}
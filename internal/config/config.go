@@ -4,6 +4,7 @@
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -15,12 +16,87 @@
 type Config struct {
 	storage   storage
 	instagram instagram
+	media     mediaConfig
+	api       api
+}
+
+type api struct {
+	listen string
+	tokens []string
+}
+
+type mediaConfig struct {
+	overlays map[string]Overlay
+}
+
+// Overlay is a watermark and text lines drawn over the account photos.
+type Overlay struct {
+	// Types are media types to draw overlays on, all photo types when empty.
+	Types     []string          `mapstructure:"types"`
+	Watermark *WatermarkOverlay `mapstructure:"watermark"`
+	Texts     []TextOverlay     `mapstructure:"texts"`
+}
+
+// WatermarkOverlay is an image, e.g. logo, drawn over the photo.
+type WatermarkOverlay struct {
+	Path     string  `mapstructure:"path"`
+	Position string  `mapstructure:"position"`
+	Scale    float64 `mapstructure:"scale"`
+	Opacity  float64 `mapstructure:"opacity"`
+	Margin   int     `mapstructure:"margin"`
+}
+
+// TextOverlay is a text line drawn over the photo.
+type TextOverlay struct {
+	Text     string  `mapstructure:"text"`
+	Position string  `mapstructure:"position"`
+	Size     float64 `mapstructure:"size"`
+	Color    string  `mapstructure:"color"`
+	Opacity  float64 `mapstructure:"opacity"`
+	Margin   int     `mapstructure:"margin"`
 }
 
 type instagram struct {
 	whitelist []string
 	limits    limits
 	sleep     int64
+	session   session
+	retry     retry
+	pacing    pacing
+	http      httpConfig
+}
+
+type httpConfig struct {
+	proxy                 string
+	accountProxies        map[string]string
+	timeout               int64
+	dialTimeout           int64
+	tlsHandshakeTimeout   int64
+	responseHeaderTimeout int64
+	tlsMinVersion         string
+	tlsInsecureSkipVerify bool
+}
+
+type pacing struct {
+	read  pacingRule
+	write pacingRule
+}
+
+type pacingRule struct {
+	perMinute float64
+	burst     int
+	jitter    int64
+}
+
+type retry struct {
+	attempts       int
+	delay          int64
+	rateLimitDelay int64
+	maxDelay       int64
+}
+
+type session struct {
+	encrypt bool
 }
 
 type limits struct {
@@ -47,6 +123,101 @@ func (c Config) Sleep() time.Duration {
 	return time.Second * time.Duration(c.instagram.sleep)
 }
 
+// RetryAttempts returns max number of attempts for failed instagram requests.
+func (c Config) RetryAttempts() int {
+	return c.instagram.retry.attempts
+}
+
+// RetryDelay returns initial backoff for retrying failed instagram requests.
+func (c Config) RetryDelay() time.Duration {
+	return time.Second * time.Duration(c.instagram.retry.delay)
+}
+
+// RetryRateLimitDelay returns initial backoff for retrying rate limited instagram requests.
+func (c Config) RetryRateLimitDelay() time.Duration {
+	return time.Second * time.Duration(c.instagram.retry.rateLimitDelay)
+}
+
+// RetryMaxDelay returns max backoff for retrying failed instagram requests.
+func (c Config) RetryMaxDelay() time.Duration {
+	return time.Second * time.Duration(c.instagram.retry.maxDelay)
+}
+
+// PacingReadPerMinute returns number of read requests allowed per minute.
+func (c Config) PacingReadPerMinute() float64 {
+	return c.instagram.pacing.read.perMinute
+}
+
+// PacingReadBurst returns number of read requests that could be done without waiting.
+func (c Config) PacingReadBurst() int {
+	return c.instagram.pacing.read.burst
+}
+
+// PacingReadJitter returns max random delay added to each read request.
+func (c Config) PacingReadJitter() time.Duration {
+	return time.Second * time.Duration(c.instagram.pacing.read.jitter)
+}
+
+// PacingWritePerMinute returns number of write requests (actions, uploads) allowed per minute.
+func (c Config) PacingWritePerMinute() float64 {
+	return c.instagram.pacing.write.perMinute
+}
+
+// PacingWriteBurst returns number of write requests that could be done without waiting.
+func (c Config) PacingWriteBurst() int {
+	return c.instagram.pacing.write.burst
+}
+
+// PacingWriteJitter returns max random delay added to each write request.
+func (c Config) PacingWriteJitter() time.Duration {
+	return time.Second * time.Duration(c.instagram.pacing.write.jitter)
+}
+
+// EncryptSession returns whether session file should be encrypted at rest.
+func (c Config) EncryptSession() bool {
+	return c.instagram.session.encrypt
+}
+
+// HTTPProxy returns proxy URL for instagram requests.
+func (c Config) HTTPProxy() string {
+	return c.instagram.http.proxy
+}
+
+// HTTPAccountProxies returns proxy URLs per account username.
+func (c Config) HTTPAccountProxies() map[string]string {
+	return c.instagram.http.accountProxies
+}
+
+// HTTPTimeout returns timeout for the whole instagram request.
+func (c Config) HTTPTimeout() time.Duration {
+	return time.Second * time.Duration(c.instagram.http.timeout)
+}
+
+// HTTPDialTimeout returns timeout for establishing connection.
+func (c Config) HTTPDialTimeout() time.Duration {
+	return time.Second * time.Duration(c.instagram.http.dialTimeout)
+}
+
+// HTTPTLSHandshakeTimeout returns timeout for TLS handshake.
+func (c Config) HTTPTLSHandshakeTimeout() time.Duration {
+	return time.Second * time.Duration(c.instagram.http.tlsHandshakeTimeout)
+}
+
+// HTTPResponseHeaderTimeout returns timeout for waiting response headers.
+func (c Config) HTTPResponseHeaderTimeout() time.Duration {
+	return time.Second * time.Duration(c.instagram.http.responseHeaderTimeout)
+}
+
+// HTTPTLSMinVersion returns minimal TLS version, e.g. "1.2".
+func (c Config) HTTPTLSMinVersion() string {
+	return c.instagram.http.tlsMinVersion
+}
+
+// HTTPTLSInsecureSkipVerify returns whether server certificate verification is disabled.
+func (c Config) HTTPTLSInsecureSkipVerify() bool {
+	return c.instagram.http.tlsInsecureSkipVerify
+}
+
 // Whitelist returns map of whitelisted users.
 func (c Config) Whitelist() map[string]struct{} {
 	if len(c.instagram.whitelist) == 0 {
@@ -61,6 +232,23 @@ func (c Config) Whitelist() map[string]struct{} {
 	return wl
 }
 
+// MediaOverlay returns overlays configured for the account username.
+func (c Config) MediaOverlay(username string) (Overlay, bool) {
+	o, ok := c.media.overlays[strings.ToLower(username)]
+
+	return o, ok
+}
+
+// APIListen returns address the API server listens on.
+func (c Config) APIListen() string {
+	return c.api.listen
+}
+
+// APITokens returns tokens that authenticate API requests.
+func (c Config) APITokens() []string {
+	return c.api.tokens
+}
+
 // IsLocalDBEnabled returns local DB enabled status.
 func (c Config) IsLocalDBEnabled() bool {
 	return c.storage.local
@@ -76,6 +264,14 @@ func (c Config) MongoDBName() string {
 	return c.storage.mongo.db
 }
 
+func loadPacingRule(key string) pacingRule {
+	return pacingRule{
+		perMinute: viper.GetFloat64(key + ".per_minute"),
+		burst:     viper.GetInt(key + ".burst"),
+		jitter:    viper.GetInt64(key + ".jitter"),
+	}
+}
+
 // Load loads config from passed filepath.
 func Load(ctx context.Context, path string) (Config, error) {
 	var cfg Config
@@ -104,6 +300,20 @@ func Load(ctx context.Context, path string) (Config, error) {
 	// Confirms which config file is used.
 	log.WithField(ctx, "config_path", viper.ConfigFileUsed()).Info("Using config file")
 
+	var overlays map[string]Overlay
+
+	// keys are lowercased by viper, usernames are looked up lowercased as well.
+	if err := viper.UnmarshalKey("media.overlays", &overlays); err != nil {
+		return Config{}, fmt.Errorf("decode media overlays: %w", err)
+	}
+
+	// relative watermark path is resolved against the config file directory, not the working one.
+	for _, o := range overlays {
+		if o.Watermark != nil && o.Watermark.Path != "" && !filepath.IsAbs(o.Watermark.Path) {
+			o.Watermark.Path = filepath.Join(filepath.Dir(path), o.Watermark.Path)
+		}
+	}
+
 	cfg = Config{
 		storage: storage{
 			local: viper.GetBool("storage.local"),
@@ -118,6 +328,36 @@ func Load(ctx context.Context, path string) (Config, error) {
 				unfollow: viper.GetInt("instagram.limits.unfollow"),
 			},
 			sleep: viper.GetInt64("instagram.sleep"),
+			session: session{
+				encrypt: viper.GetBool("instagram.session.encrypt"),
+			},
+			retry: retry{
+				attempts:       viper.GetInt("instagram.retry.attempts"),
+				delay:          viper.GetInt64("instagram.retry.delay"),
+				rateLimitDelay: viper.GetInt64("instagram.retry.rate_limit_delay"),
+				maxDelay:       viper.GetInt64("instagram.retry.max_delay"),
+			},
+			pacing: pacing{
+				read:  loadPacingRule("instagram.pacing.read"),
+				write: loadPacingRule("instagram.pacing.write"),
+			},
+			http: httpConfig{
+				proxy:                 viper.GetString("instagram.http.proxy"),
+				accountProxies:        viper.GetStringMapString("instagram.http.account_proxies"),
+				timeout:               viper.GetInt64("instagram.http.timeout"),
+				dialTimeout:           viper.GetInt64("instagram.http.dial_timeout"),
+				tlsHandshakeTimeout:   viper.GetInt64("instagram.http.tls_handshake_timeout"),
+				responseHeaderTimeout: viper.GetInt64("instagram.http.response_header_timeout"),
+				tlsMinVersion:         viper.GetString("instagram.http.tls_min_version"),
+				tlsInsecureSkipVerify: viper.GetBool("instagram.http.tls_insecure_skip_verify"),
+			},
+		},
+		media: mediaConfig{
+			overlays: overlays,
+		},
+		api: api{
+			listen: viper.GetString("api.listen"),
+			tokens: viper.GetStringSlice("api.tokens"),
 		},
 	}
 
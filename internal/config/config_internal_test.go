@@ -37,6 +37,67 @@ func TestLoad(t *testing.T) {
 						unfollow: 100,
 					},
 					sleep: 1,
+					session: session{
+						encrypt: true,
+					},
+					retry: retry{
+						attempts:       3,
+						delay:          2,
+						rateLimitDelay: 30,
+						maxDelay:       300,
+					},
+					pacing: pacing{
+						read: pacingRule{
+							perMinute: 30,
+							burst:     5,
+							jitter:    2,
+						},
+						write: pacingRule{
+							perMinute: 6,
+							burst:     1,
+							jitter:    5,
+						},
+					},
+					http: httpConfig{
+						proxy: "http://127.0.0.1:8080",
+						accountProxies: map[string]string{
+							"user1": "socks5://127.0.0.1:1080",
+						},
+						timeout:               60,
+						dialTimeout:           10,
+						tlsHandshakeTimeout:   10,
+						responseHeaderTimeout: 30,
+						tlsMinVersion:         "1.2",
+						tlsInsecureSkipVerify: false,
+					},
+				},
+				media: mediaConfig{
+					overlays: map[string]Overlay{
+						"user1": {
+							Types: []string{"feed_photo", "carousel"},
+							Watermark: &WatermarkOverlay{
+								Path:     filepath.Join("testdata", "logo.png"),
+								Position: "bottom_right",
+								Scale:    0.2,
+								Opacity:  0.8,
+								Margin:   20,
+							},
+							Texts: []TextOverlay{
+								{
+									Text:     "@user1",
+									Position: "bottom_left",
+									Size:     32,
+									Color:    "#FFFFFF",
+									Opacity:  0.9,
+									Margin:   20,
+								},
+							},
+						},
+					},
+				},
+				api: api{
+					listen: "127.0.0.1:8080",
+					tokens: []string{"change-me"},
 				},
 				storage: storage{
 					local: true,
@@ -72,3 +133,15 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_MediaOverlay(t *testing.T) {
+	cfg, err := Load(context.Background(), filepath.Join("testdata", "config-test.json"))
+	assert.NoError(t, err)
+
+	got, ok := cfg.MediaOverlay("USER1")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join("testdata", "logo.png"), got.Watermark.Path)
+
+	_, ok = cfg.MediaOverlay("user2")
+	assert.False(t, ok)
+}
@@ -15,6 +15,30 @@ type DB interface {
 	GetLastUsersBatchByType(ctx context.Context, batchType models.UsersBatchType) (models.UsersBatch, error)
 	// GetAllUsersBatchByType returns all users batches by passed batch type.
 	GetAllUsersBatchByType(ctx context.Context, batchType models.UsersBatchType) ([]models.UsersBatch, error)
+	// InsertEngagement creates record in database with users engagement.
+	InsertEngagement(ctx context.Context, e models.Engagement) error
+	// GetLastEngagement returns last created users engagement.
+	GetLastEngagement(ctx context.Context) (models.Engagement, error)
+	// InsertPostsMetrics creates record in database with posts metrics snapshot.
+	InsertPostsMetrics(ctx context.Context, m models.PostsMetrics) error
+	// GetAllPostsMetrics returns all posts metrics snapshots, oldest first.
+	GetAllPostsMetrics(ctx context.Context) ([]models.PostsMetrics, error)
+	// InsertStoryViewers creates record in database with story viewers.
+	InsertStoryViewers(ctx context.Context, sv models.StoryViewers) error
+	// GetAllStoryViewers returns all stored story viewers records, oldest first.
+	GetAllStoryViewers(ctx context.Context) ([]models.StoryViewers, error)
+	// InsertUpload creates record in database with uploaded file.
+	InsertUpload(ctx context.Context, u models.Upload) error
+	// GetAllUploads returns all uploaded files records, oldest first.
+	GetAllUploads(ctx context.Context) ([]models.Upload, error)
+	// InsertQueueItem creates record in database with scheduled post.
+	InsertQueueItem(ctx context.Context, item models.QueueItem) error
+	// UpdateQueueItem replaces scheduled post with the same id, returns ErrNoData when there is no such post.
+	UpdateQueueItem(ctx context.Context, item models.QueueItem) error
+	// DeleteQueueItem removes scheduled post by id, returns ErrNoData when there is no such post.
+	DeleteQueueItem(ctx context.Context, id string) error
+	// GetAllQueueItems returns all scheduled posts, the earliest scheduled first.
+	GetAllQueueItems(ctx context.Context) ([]models.QueueItem, error)
 	// Close closes connections.
 	Close(ctx context.Context) error
 }
@@ -3,13 +3,22 @@
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/obalunenko/instadiff-cli/internal/models"
 )
 
+// localDB keeps data in memory, it is safe for concurrent use.
 type localDB struct {
-	users map[models.UsersBatchType][]models.UsersBatch
+	mu           sync.RWMutex
+	users        map[models.UsersBatchType][]models.UsersBatch
+	engagement   []models.Engagement
+	postsMetrics []models.PostsMetrics
+	storyViewers []models.StoryViewers
+	uploads      []models.Upload
+	queue        []models.QueueItem
 }
 
 func (l *localDB) Close(_ context.Context) error {
@@ -27,6 +36,9 @@ func (l *localDB) InsertUsersBatch(ctx context.Context, users models.UsersBatch)
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
 		bt := users.Type
 
 		if !bt.Valid() {
@@ -48,6 +60,9 @@ func (l *localDB) GetLastUsersBatchByType(ctx context.Context, bt models.UsersBa
 	case <-ctx.Done():
 		return models.MakeUsersBatch(bt, nil, time.Now()), ctx.Err()
 	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
 		if !bt.Valid() {
 			return models.MakeUsersBatch(bt, nil, time.Now()), models.MakeInvalidBatchTypeError(bt)
 		}
@@ -68,6 +83,9 @@ func (l *localDB) GetAllUsersBatchByType(ctx context.Context, batchType models.U
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
 		if !batchType.Valid() {
 			return nil, models.MakeInvalidBatchTypeError(batchType)
 		}
@@ -77,6 +95,202 @@ func (l *localDB) GetAllUsersBatchByType(ctx context.Context, batchType models.U
 			return nil, ErrNoData
 		}
 
-		return batches, nil
+		return slices.Clone(batches), nil
+	}
+}
+
+func (l *localDB) InsertEngagement(ctx context.Context, e models.Engagement) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.engagement = append(l.engagement, e)
+
+		return nil
+	}
+}
+
+func (l *localDB) GetLastEngagement(ctx context.Context) (models.Engagement, error) {
+	select {
+	case <-ctx.Done():
+		return models.Engagement{}, ctx.Err()
+	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		if len(l.engagement) == 0 {
+			return models.Engagement{}, ErrNoData
+		}
+
+		return l.engagement[len(l.engagement)-1], nil
+	}
+}
+
+func (l *localDB) InsertPostsMetrics(ctx context.Context, m models.PostsMetrics) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.postsMetrics = append(l.postsMetrics, m)
+
+		return nil
+	}
+}
+
+func (l *localDB) GetAllPostsMetrics(ctx context.Context) ([]models.PostsMetrics, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		if len(l.postsMetrics) == 0 {
+			return nil, ErrNoData
+		}
+
+		return slices.Clone(l.postsMetrics), nil
+	}
+}
+
+func (l *localDB) InsertStoryViewers(ctx context.Context, sv models.StoryViewers) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.storyViewers = append(l.storyViewers, sv)
+
+		return nil
+	}
+}
+
+func (l *localDB) GetAllStoryViewers(ctx context.Context) ([]models.StoryViewers, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		if len(l.storyViewers) == 0 {
+			return nil, ErrNoData
+		}
+
+		return slices.Clone(l.storyViewers), nil
+	}
+}
+
+func (l *localDB) InsertUpload(ctx context.Context, u models.Upload) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.uploads = append(l.uploads, u)
+
+		return nil
+	}
+}
+
+func (l *localDB) GetAllUploads(ctx context.Context) ([]models.Upload, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		if len(l.uploads) == 0 {
+			return nil, ErrNoData
+		}
+
+		return slices.Clone(l.uploads), nil
+	}
+}
+
+func (l *localDB) InsertQueueItem(ctx context.Context, item models.QueueItem) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.queue = append(l.queue, item)
+
+		return nil
+	}
+}
+
+func (l *localDB) UpdateQueueItem(ctx context.Context, item models.QueueItem) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i := range l.queue {
+			if l.queue[i].ID == item.ID {
+				l.queue[i] = item
+
+				return nil
+			}
+		}
+
+		return ErrNoData
+	}
+}
+
+func (l *localDB) DeleteQueueItem(ctx context.Context, id string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i := range l.queue {
+			if l.queue[i].ID == id {
+				l.queue = slices.Delete(l.queue, i, i+1)
+
+				return nil
+			}
+		}
+
+		return ErrNoData
+	}
+}
+
+func (l *localDB) GetAllQueueItems(ctx context.Context) ([]models.QueueItem, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		if len(l.queue) == 0 {
+			return nil, ErrNoData
+		}
+
+		items := slices.Clone(l.queue)
+
+		slices.SortStableFunc(items, func(a, b models.QueueItem) int {
+			return a.ScheduledAt.Compare(b.ScheduledAt)
+		})
+
+		return items, nil
 	}
 }
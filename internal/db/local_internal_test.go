@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -93,3 +94,160 @@ func Test_localDB_InsertUsersBatch(t *testing.T) {
 
 	assert.Equal(t, resetBatchTime(goldenBatch), resetBatchTime(gotBatch))
 }
+
+func Test_localDB_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	ldb := newLocalDB()
+
+	const workers = 10
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, ldb.InsertUsersBatch(ctx, models.MakeUsersBatch(models.UsersBatchTypeFollowers, followersFixture2, time.Now())))
+
+			_, err := ldb.GetAllUsersBatchByType(ctx, models.UsersBatchTypeFollowers)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	got, err := ldb.GetAllUsersBatchByType(ctx, models.UsersBatchTypeFollowers)
+	require.NoError(t, err)
+	assert.Len(t, got, workers)
+}
+
+func Test_localDB_Engagement(t *testing.T) {
+	ctx := context.Background()
+
+	l := newLocalDB()
+
+	_, err := l.GetLastEngagement(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	e1 := models.Engagement{
+		Users: []models.UserEngagement{{User: followersFixture1[0], Likes: 1}},
+		Posts: []models.Post{{ID: "10_1", Likes: 1}},
+	}
+
+	e2 := models.Engagement{
+		Users: []models.UserEngagement{{User: followersFixture2[0], Comments: 2}},
+		Posts: []models.Post{{ID: "11_1", Comments: 2}},
+	}
+
+	require.NoError(t, l.InsertEngagement(ctx, e1))
+	require.NoError(t, l.InsertEngagement(ctx, e2))
+
+	got, err := l.GetLastEngagement(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, e2, got)
+}
+
+func Test_localDB_PostsMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	l := newLocalDB()
+
+	_, err := l.GetAllPostsMetrics(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	m1 := models.PostsMetrics{Posts: []models.Post{{ID: "10_1", Likes: 1}}, Followers: 10}
+	m2 := models.PostsMetrics{Posts: []models.Post{{ID: "11_1", Comments: 2}}, Followers: 11}
+
+	require.NoError(t, l.InsertPostsMetrics(ctx, m1))
+	require.NoError(t, l.InsertPostsMetrics(ctx, m2))
+
+	got, err := l.GetAllPostsMetrics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.PostsMetrics{m1, m2}, got)
+}
+
+func Test_localDB_StoryViewers(t *testing.T) {
+	ctx := context.Background()
+
+	l := newLocalDB()
+
+	_, err := l.GetAllStoryViewers(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	sv1 := models.StoryViewers{Story: models.Story{ID: "20_1"}, Users: followersFixture1}
+	sv2 := models.StoryViewers{Story: models.Story{ID: "21_1"}, Users: followersFixture2}
+
+	require.NoError(t, l.InsertStoryViewers(ctx, sv1))
+	require.NoError(t, l.InsertStoryViewers(ctx, sv2))
+
+	got, err := l.GetAllStoryViewers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.StoryViewers{sv1, sv2}, got)
+}
+
+func Test_localDB_Uploads(t *testing.T) {
+	ctx := context.Background()
+
+	l := newLocalDB()
+
+	_, err := l.GetAllUploads(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	u1 := models.Upload{Hash: "aa", File: "1.jpg", Type: "feed_photo", MediaID: "1_1"}
+	u2 := models.Upload{Hash: "bb", File: "2.jpg", Type: "story_photo"}
+
+	require.NoError(t, l.InsertUpload(ctx, u1))
+	require.NoError(t, l.InsertUpload(ctx, u2))
+
+	got, err := l.GetAllUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Upload{u1, u2}, got)
+
+	// returned list is a copy, stored records are not changed.
+	got[0].MediaID = "changed"
+
+	got, err = l.GetAllUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, u1, got[0])
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = l.GetAllUploads(canceled)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_localDB_Queue(t *testing.T) {
+	ctx := context.Background()
+
+	l := newLocalDB()
+
+	_, err := l.GetAllQueueItems(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	late := models.QueueItem{ID: "b", ScheduledAt: day1.AddDate(0, 0, 1), Status: models.QueueStatusPending}
+	early := models.QueueItem{ID: "a", ScheduledAt: day1, Status: models.QueueStatusPending}
+
+	require.NoError(t, l.InsertQueueItem(ctx, late))
+	require.NoError(t, l.InsertQueueItem(ctx, early))
+
+	got, err := l.GetAllQueueItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.QueueItem{early, late}, got)
+
+	late.Status = models.QueueStatusDone
+
+	require.NoError(t, l.UpdateQueueItem(ctx, late))
+	require.ErrorIs(t, l.UpdateQueueItem(ctx, models.QueueItem{ID: "c"}), ErrNoData)
+
+	require.NoError(t, l.DeleteQueueItem(ctx, "a"))
+	require.ErrorIs(t, l.DeleteQueueItem(ctx, "a"), ErrNoData)
+
+	got, err = l.GetAllQueueItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.QueueItem{late}, got)
+}
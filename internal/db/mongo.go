@@ -5,6 +5,7 @@
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,15 +17,25 @@
 )
 
 // BuildCollectionName constructs collection name.
+// Username is lowercased, instagram usernames are case-insensitive.
 func BuildCollectionName(s string) string {
 	const (
 		sep = "_"
 		pfx = "statistics"
 	)
 
-	return s + sep + pfx
+	return strings.ToLower(strings.TrimSpace(s)) + sep + pfx
 }
 
+// Suffixes are added to the collection name for records that differ from users batches.
+const (
+	engagementSfx   = "_engagement"
+	postsMetricsSfx = "_posts_metrics"
+	storyViewersSfx = "_story_viewers"
+	uploadsSfx      = "_uploads"
+	queueSfx        = "_queue"
+)
+
 // MongoParams represents mongo db configuration parameters.
 type MongoParams struct {
 	URL        string
@@ -36,6 +47,16 @@ type mongoDB struct {
 	client     *mongo.Client
 	database   *mongo.Database
 	collection *mongo.Collection
+	// engagement stores users engagement, documents differ from users batches.
+	engagement *mongo.Collection
+	// postsMetrics stores posts metrics snapshots.
+	postsMetrics *mongo.Collection
+	// storyViewers stores story viewers records.
+	storyViewers *mongo.Collection
+	// uploads stores uploaded files records.
+	uploads *mongo.Collection
+	// queue stores scheduled posts.
+	queue *mongo.Collection
 }
 
 // Close closes connections.
@@ -57,9 +78,14 @@ func newMongoDB(ctx context.Context, params MongoParams) (*mongoDB, error) {
 	collection := database.Collection(params.Collection)
 
 	return &mongoDB{
-		client:     cl,
-		database:   database,
-		collection: collection,
+		client:       cl,
+		database:     database,
+		collection:   collection,
+		engagement:   database.Collection(params.Collection + engagementSfx),
+		postsMetrics: database.Collection(params.Collection + postsMetricsSfx),
+		storyViewers: database.Collection(params.Collection + storyViewersSfx),
+		uploads:      database.Collection(params.Collection + uploadsSfx),
+		queue:        database.Collection(params.Collection + queueSfx),
 	}, nil
 }
 
@@ -133,3 +159,231 @@ func (m *mongoDB) GetAllUsersBatchByType(ctx context.Context, bt models.UsersBat
 
 	return batches, nil
 }
+
+func (m *mongoDB) InsertEngagement(ctx context.Context, e models.Engagement) error {
+	if _, err := m.engagement.InsertOne(ctx, e); err != nil {
+		return fmt.Errorf("insert engagement: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoDB) GetLastEngagement(ctx context.Context) (models.Engagement, error) {
+	resp := m.engagement.FindOne(ctx, bson.M{}, &options.FindOneOptions{
+		Sort: bson.M{"$natural": -1},
+	})
+
+	if err := resp.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Engagement{}, ErrNoData
+		}
+
+		return models.Engagement{}, fmt.Errorf("find engagement: %w", err)
+	}
+
+	var e models.Engagement
+
+	if err := resp.Decode(&e); err != nil {
+		return models.Engagement{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	return e, nil
+}
+
+func (m *mongoDB) InsertPostsMetrics(ctx context.Context, pm models.PostsMetrics) error {
+	if _, err := m.postsMetrics.InsertOne(ctx, pm); err != nil {
+		return fmt.Errorf("insert posts metrics: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoDB) GetAllPostsMetrics(ctx context.Context) ([]models.PostsMetrics, error) {
+	resp, err := m.postsMetrics.Find(ctx, bson.M{}, &options.FindOptions{
+		Sort: bson.M{"created_at": 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find posts metrics: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, resp.Close(ctx), "mongo: Failed to close cursor")
+	}()
+
+	var metrics []models.PostsMetrics
+
+	for resp.Next(ctx) {
+		var pm models.PostsMetrics
+
+		if err := resp.Decode(&pm); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+
+		metrics = append(metrics, pm)
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, fmt.Errorf("iterate posts metrics: %w", err)
+	}
+
+	if len(metrics) == 0 {
+		return nil, ErrNoData
+	}
+
+	return metrics, nil
+}
+
+func (m *mongoDB) InsertStoryViewers(ctx context.Context, sv models.StoryViewers) error {
+	if _, err := m.storyViewers.InsertOne(ctx, sv); err != nil {
+		return fmt.Errorf("insert story viewers: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoDB) GetAllStoryViewers(ctx context.Context) ([]models.StoryViewers, error) {
+	resp, err := m.storyViewers.Find(ctx, bson.M{}, &options.FindOptions{
+		Sort: bson.M{"created_at": 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find story viewers: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, resp.Close(ctx), "mongo: Failed to close cursor")
+	}()
+
+	var records []models.StoryViewers
+
+	for resp.Next(ctx) {
+		var sv models.StoryViewers
+
+		if err := resp.Decode(&sv); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+
+		records = append(records, sv)
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, fmt.Errorf("iterate story viewers: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, ErrNoData
+	}
+
+	return records, nil
+}
+
+func (m *mongoDB) InsertUpload(ctx context.Context, u models.Upload) error {
+	if _, err := m.uploads.InsertOne(ctx, u); err != nil {
+		return fmt.Errorf("insert upload: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoDB) GetAllUploads(ctx context.Context) ([]models.Upload, error) {
+	resp, err := m.uploads.Find(ctx, bson.M{}, &options.FindOptions{
+		Sort: bson.M{"created_at": 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find uploads: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, resp.Close(ctx), "mongo: Failed to close cursor")
+	}()
+
+	var uploads []models.Upload
+
+	for resp.Next(ctx) {
+		var u models.Upload
+
+		if err := resp.Decode(&u); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+
+		uploads = append(uploads, u)
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, fmt.Errorf("iterate uploads: %w", err)
+	}
+
+	if len(uploads) == 0 {
+		return nil, ErrNoData
+	}
+
+	return uploads, nil
+}
+
+func (m *mongoDB) InsertQueueItem(ctx context.Context, item models.QueueItem) error {
+	if _, err := m.queue.InsertOne(ctx, item); err != nil {
+		return fmt.Errorf("insert queue item: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoDB) UpdateQueueItem(ctx context.Context, item models.QueueItem) error {
+	res, err := m.queue.ReplaceOne(ctx, bson.M{"id": item.ID}, item)
+	if err != nil {
+		return fmt.Errorf("replace queue item: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNoData
+	}
+
+	return nil
+}
+
+func (m *mongoDB) DeleteQueueItem(ctx context.Context, id string) error {
+	res, err := m.queue.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("delete queue item: %w", err)
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrNoData
+	}
+
+	return nil
+}
+
+func (m *mongoDB) GetAllQueueItems(ctx context.Context) ([]models.QueueItem, error) {
+	resp, err := m.queue.Find(ctx, bson.M{}, &options.FindOptions{
+		Sort: bson.M{"scheduled_at": 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find queue items: %w", err)
+	}
+
+	defer func() {
+		utils.LogError(ctx, resp.Close(ctx), "mongo: Failed to close cursor")
+	}()
+
+	var items []models.QueueItem
+
+	for resp.Next(ctx) {
+		var item models.QueueItem
+
+		if err := resp.Decode(&item); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, fmt.Errorf("iterate queue items: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil, ErrNoData
+	}
+
+	return items, nil
+}
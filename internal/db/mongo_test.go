@@ -9,6 +9,7 @@
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/obalunenko/instadiff-cli/internal/media"
 	"github.com/obalunenko/instadiff-cli/internal/models"
 )
 
@@ -85,3 +86,192 @@ func TestMongoDB(t *testing.T) {
 
 	assert.Equal(t, resetBatchTime(b2), resetBatchTime(gotbatch))
 }
+
+func TestMongoDB_Engagement(t *testing.T) {
+	ctx := context.Background()
+
+	dbc := ConnectForTesting(t, "", BuildCollectionName("test_engagement"))
+
+	_, err := dbc.GetLastEngagement(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	created := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	e := models.Engagement{
+		Users: []models.UserEngagement{{User: followersFixture1[0], Likes: 1, Comments: 2}},
+		Posts: []models.Post{{
+			ID:       "10_1",
+			Code:     "abc",
+			TakenAt:  created,
+			Likes:    1,
+			Comments: 2,
+		}},
+		CreatedAt: created,
+	}
+
+	require.NoError(t, dbc.InsertEngagement(ctx, e))
+
+	got, err := dbc.GetLastEngagement(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, e.Users, got.Users)
+	assert.Equal(t, e.Posts[0].ID, got.Posts[0].ID)
+	assert.True(t, e.CreatedAt.Equal(got.CreatedAt))
+}
+
+func TestMongoDB_PostsMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	dbc := ConnectForTesting(t, "", BuildCollectionName("test_posts_metrics"))
+
+	_, err := dbc.GetAllPostsMetrics(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	require.NoError(t, dbc.InsertPostsMetrics(ctx, models.PostsMetrics{
+		Posts:     []models.Post{{ID: "11_1", Likes: 3}},
+		Followers: 11,
+		CreatedAt: day2,
+	}))
+	require.NoError(t, dbc.InsertPostsMetrics(ctx, models.PostsMetrics{
+		Posts:     []models.Post{{ID: "10_1", Likes: 1}},
+		Followers: 10,
+		CreatedAt: day1,
+	}))
+
+	got, err := dbc.GetAllPostsMetrics(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.True(t, day1.Equal(got[0].CreatedAt))
+	assert.Equal(t, 10, got[0].Followers)
+	assert.True(t, day2.Equal(got[1].CreatedAt))
+}
+
+func TestMongoDB_StoryViewers(t *testing.T) {
+	ctx := context.Background()
+
+	dbc := ConnectForTesting(t, "", BuildCollectionName("test_story_viewers"))
+
+	_, err := dbc.GetAllStoryViewers(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	created := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, dbc.InsertStoryViewers(ctx, models.StoryViewers{
+		Story:     models.Story{ID: "20_1", TakenAt: created, ExpiresAt: created.Add(24 * time.Hour)},
+		Users:     followersFixture1,
+		CreatedAt: created,
+	}))
+
+	got, err := dbc.GetAllStoryViewers(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	assert.Equal(t, "20_1", got[0].Story.ID)
+	assert.Equal(t, followersFixture1, got[0].Users)
+}
+
+func TestMongoDB_Uploads(t *testing.T) {
+	ctx := context.Background()
+
+	dbc := ConnectForTesting(t, "", BuildCollectionName("test_uploads"))
+
+	_, err := dbc.GetAllUploads(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	require.NoError(t, dbc.InsertUpload(ctx, models.Upload{Hash: "bb", File: "2.jpg", Type: "feed_photo", CreatedAt: day2}))
+	require.NoError(t, dbc.InsertUpload(ctx, models.Upload{Hash: "aa", File: "1.jpg", Type: "feed_photo", MediaID: "1_1", CreatedAt: day1}))
+
+	got, err := dbc.GetAllUploads(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "aa", got[0].Hash)
+	assert.Equal(t, "1_1", got[0].MediaID)
+	assert.True(t, day2.Equal(got[1].CreatedAt))
+}
+
+func TestMongoDB_Queue(t *testing.T) {
+	ctx := context.Background()
+
+	dbc := ConnectForTesting(t, "", BuildCollectionName("test_queue"))
+
+	_, err := dbc.GetAllQueueItems(ctx)
+	require.ErrorIs(t, err, ErrNoData)
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, dbc.InsertQueueItem(ctx, models.QueueItem{
+		ID:          "b",
+		Files:       []string{"/tmp/b.jpg"},
+		Type:        media.TypeFeedPhoto,
+		Post:        media.PostOptions{Caption: "later"},
+		ScheduledAt: day1.AddDate(0, 0, 1),
+		Status:      models.QueueStatusPending,
+	}))
+	require.NoError(t, dbc.InsertQueueItem(ctx, models.QueueItem{
+		ID:          "a",
+		ScheduledAt: day1,
+		Status:      models.QueueStatusPending,
+	}))
+
+	require.NoError(t, dbc.UpdateQueueItem(ctx, models.QueueItem{
+		ID:          "b",
+		Files:       []string{"/tmp/b.jpg"},
+		Type:        media.TypeFeedPhoto,
+		Post:        media.PostOptions{Caption: "later"},
+		ScheduledAt: day1.AddDate(0, 0, 1),
+		Status:      models.QueueStatusDone,
+	}))
+	require.ErrorIs(t, dbc.UpdateQueueItem(ctx, models.QueueItem{ID: "c"}), ErrNoData)
+
+	require.NoError(t, dbc.DeleteQueueItem(ctx, "a"))
+	require.ErrorIs(t, dbc.DeleteQueueItem(ctx, "a"), ErrNoData)
+
+	got, err := dbc.GetAllQueueItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	assert.Equal(t, "b", got[0].ID)
+	assert.Equal(t, models.QueueStatusDone, got[0].Status)
+	assert.Equal(t, "later", got[0].Post.Caption)
+	assert.Equal(t, media.TypeFeedPhoto, got[0].Type)
+}
+
+func TestBuildCollectionName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "lowercase",
+			in:   "tester",
+			want: "tester_statistics",
+		},
+		{
+			name: "mixed case",
+			in:   "Tester",
+			want: "tester_statistics",
+		},
+		{
+			name: "spaces",
+			in:   " tester ",
+			want: "tester_statistics",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildCollectionName(tt.in))
+		})
+	}
+}
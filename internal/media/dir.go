@@ -0,0 +1,134 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// imageExts are file extensions of the supported photo formats.
+var imageExts = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp", ".tif", ".tiff"}
+
+// DirItem represents file of the directory to upload.
+type DirItem struct {
+	// File is a path to the file.
+	File string
+	// Type is a photo media type: feed_photo (default) or story_photo.
+	Type Type
+	// Post is a caption and other post options.
+	Post PostOptions
+}
+
+// dirManifest is a directory manifest: files in upload order with their post options.
+type dirManifest struct {
+	Items []struct {
+		File string      `json:"file" yaml:"file"`
+		Type string      `json:"type" yaml:"type"`
+		Post PostOptions `json:"post" yaml:"post"`
+	} `json:"items" yaml:"items"`
+}
+
+// ReadDir returns photos of the directory to upload.
+// Without manifest all photos are returned sorted by name as feed photos without post options,
+// otherwise only manifest items are returned in the manifest order.
+func ReadDir(dir, manifestPath string) ([]DirItem, error) {
+	if manifestPath == "" {
+		return listImages(dir)
+	}
+
+	m, err := loadDirManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]DirItem, 0, len(m.Items))
+
+	for i, it := range m.Items {
+		if it.File == "" {
+			return nil, fmt.Errorf("manifest item %d: file is not set: %w", i+1, ErrInvalidManifest)
+		}
+
+		mt := TypeFeedPhoto
+
+		if it.Type != "" {
+			if mt, err = Parse(it.Type); err != nil {
+				return nil, fmt.Errorf("manifest item %d: %w", i+1, err)
+			}
+		}
+
+		if mt != TypeFeedPhoto && mt != TypeStoryPhoto {
+			return nil, fmt.Errorf("manifest item %d: type %s is not a single photo: %w", i+1, mt.String(), ErrInvalidManifest)
+		}
+
+		// files outside of the directory are refused, e.g. ../secret.jpg or absolute paths.
+		if !filepath.IsLocal(it.File) {
+			return nil, fmt.Errorf("manifest item %d: file %s is outside of the directory: %w", i+1, it.File, ErrInvalidManifest)
+		}
+
+		path := filepath.Join(dir, filepath.Clean(it.File))
+
+		if _, err = os.Stat(path); err != nil {
+			return nil, fmt.Errorf("manifest item %d: %w", i+1, err)
+		}
+
+		items = append(items, DirItem{
+			File: path,
+			Type: mt,
+			Post: it.Post,
+		})
+	}
+
+	return items, nil
+}
+
+func listImages(dir string) ([]DirItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir: %w", err)
+	}
+
+	var items []DirItem
+
+	// entries are sorted by file name.
+	for _, e := range entries {
+		if e.IsDir() || !slices.Contains(imageExts, strings.ToLower(filepath.Ext(e.Name()))) {
+			continue
+		}
+
+		items = append(items, DirItem{
+			File: filepath.Join(dir, e.Name()),
+			Type: TypeFeedPhoto,
+		})
+	}
+
+	return items, nil
+}
+
+func loadDirManifest(path string) (dirManifest, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return dirManifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m dirManifest
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(content, &m)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &m)
+	default:
+		return dirManifest{}, fmt.Errorf("manifest extension [%s]: %w", ext, ErrManifestFormat)
+	}
+
+	if err != nil {
+		return dirManifest{}, fmt.Errorf("decode manifest: %w", err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,144 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.jpg"), 0o700))
+
+	writeFiles(t, dir, map[string]string{
+		"b.JPG":     "",
+		"a.png":     "",
+		"c.webp":    "",
+		"notes.txt": "",
+		"ok.yaml": `items:
+  - file: c.webp
+    post:
+      caption: first
+      hashtags: [go]
+  - file: a.png
+    type: story_photo
+`,
+		"ok.json":      `{"items": [{"file": "b.JPG", "post": {"caption": "json"}}]}`,
+		"missing.yaml": "items:\n  - file: x.jpg\n",
+		"video.yaml":   "items:\n  - file: a.png\n    type: video\n",
+		"nofile.yaml":  "items:\n  - type: feed_photo\n",
+		// file exists, but path leaves the directory.
+		"escape.yaml":   "items:\n  - file: ../" + filepath.Base(dir) + "/a.png\n",
+		"absolute.yaml": "items:\n  - file: " + filepath.Join(dir, "a.png") + "\n",
+		"manifest.txt":  "",
+	})
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     []DirItem
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "no manifest",
+			manifest: "",
+			want: []DirItem{
+				{File: filepath.Join(dir, "a.png"), Type: TypeFeedPhoto},
+				{File: filepath.Join(dir, "b.JPG"), Type: TypeFeedPhoto},
+				{File: filepath.Join(dir, "c.webp"), Type: TypeFeedPhoto},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "yaml manifest",
+			manifest: "ok.yaml",
+			want: []DirItem{
+				{
+					File: filepath.Join(dir, "c.webp"),
+					Type: TypeFeedPhoto,
+					Post: PostOptions{Caption: "first", Hashtags: []string{"go"}},
+				},
+				{File: filepath.Join(dir, "a.png"), Type: TypeStoryPhoto},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "json manifest",
+			manifest: "ok.json",
+			want: []DirItem{
+				{File: filepath.Join(dir, "b.JPG"), Type: TypeFeedPhoto, Post: PostOptions{Caption: "json"}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "missing file",
+			manifest: "missing.yaml",
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "video type",
+			manifest: "video.yaml",
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidManifest)
+			},
+		},
+		{
+			name:     "file not set",
+			manifest: "nofile.yaml",
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidManifest)
+			},
+		},
+		{
+			name:     "path outside of directory",
+			manifest: "escape.yaml",
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidManifest)
+			},
+		},
+		{
+			name:     "absolute path",
+			manifest: "absolute.yaml",
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrInvalidManifest)
+			},
+		},
+		{
+			name:     "unsupported manifest format",
+			manifest: "manifest.txt",
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrManifestFormat)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var manifest string
+			if tt.manifest != "" {
+				manifest = filepath.Join(dir, tt.manifest)
+			}
+
+			got, err := ReadDir(dir, manifest)
+			if !tt.wantErr(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+)
+
+// MaxPhotoSize is the biggest photo file instagram accepts.
+const MaxPhotoSize = 8 << 20
+
+const (
+	defaultQuality = 90
+	minQuality     = 50
+	qualityStep    = 5
+)
+
+// Encoding describes how processed photo is written.
+type Encoding struct {
+	// Quality is a JPEG quality from 1 to 100.
+	Quality int
+	// MaxSize is a file size target in bytes, quality is lowered down to 50 to fit it. Zero means no limit.
+	MaxSize int
+	// StripMetadata drops EXIF (GPS, camera, date) from result. Otherwise source EXIF is kept with orientation reset.
+	StripMetadata bool
+}
+
+// DefaultEncoding returns encoding that fits instagram limits and drops metadata.
+func DefaultEncoding() Encoding {
+	return Encoding{
+		Quality:       defaultQuality,
+		MaxSize:       MaxPhotoSize,
+		StripMetadata: true,
+	}
+}
+
+// Validate checks encoding values.
+func (e Encoding) Validate() error {
+	if e.Quality < 1 || e.Quality > 100 {
+		return fmt.Errorf("quality %d should be from 1 to 100: %w", e.Quality, ErrInvalidEncoding)
+	}
+
+	if e.MaxSize < 0 {
+		return fmt.Errorf("max size %d should not be negative: %w", e.MaxSize, ErrInvalidEncoding)
+	}
+
+	return nil
+}
+
+// encode writes image to JPEG with metadata, lowering quality until result fits the size target.
+func (e Encoding) encode(img image.Image, exif []byte) (io.Reader, error) {
+	q := e.Quality
+
+	for {
+		buf, err := encodeJPEG(img, q, exif)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.MaxSize == 0 || buf.Len() <= e.MaxSize {
+			return buf, nil
+		}
+
+		if q <= minQuality {
+			return nil, fmt.Errorf("%d bytes at quality %d, limit %d: %w", buf.Len(), q, e.MaxSize, ErrFileTooLarge)
+		}
+
+		q = max(q-qualityStep, minQuality)
+	}
+}
+
+func encodeJPEG(img image.Image, quality int, exif []byte) (*bytes.Buffer, error) {
+	var enc bytes.Buffer
+
+	if err := jpeg.Encode(&enc, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	if len(exif) == 0 {
+		return &enc, nil
+	}
+
+	// EXIF segment goes right after SOI marker.
+	data := enc.Bytes()
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+len(exif)+4))
+
+	buf.Write(data[:2])
+	buf.Write([]byte{0xFF, markerAPP1, byte((len(exif) + 2) >> 8), byte(len(exif) + 2)})
+	buf.Write(exif)
+	buf.Write(data[2:])
+
+	return buf, nil
+}
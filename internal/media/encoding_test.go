@@ -0,0 +1,208 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exifWithOrientation returns big-endian EXIF payload with only orientation tag.
+func exifWithOrientation(o uint16) []byte {
+	buf := bytes.NewBuffer(bytes.Clone(exifHeader))
+
+	buf.WriteString("MM\x00\x2a")
+	_ = binary.Write(buf, binary.BigEndian, uint32(8))
+	_ = binary.Write(buf, binary.BigEndian, uint16(1))
+	_ = binary.Write(buf, binary.BigEndian, []uint16{tagOrientation, 3})
+	_ = binary.Write(buf, binary.BigEndian, uint32(1))
+	_ = binary.Write(buf, binary.BigEndian, []uint16{o, 0})
+	_ = binary.Write(buf, binary.BigEndian, uint32(0))
+
+	return buf.Bytes()
+}
+
+func orientationOf(tb testing.TB, exif []byte) uint16 {
+	tb.Helper()
+
+	require.True(tb, bytes.HasPrefix(exif, exifHeader))
+
+	tiff := exif[len(exifHeader):]
+
+	return binary.BigEndian.Uint16(tiff[8+2+8:])
+}
+
+func noiseImage(w, h int) image.Image {
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for i := range img.Pix {
+		img.Pix[i] = byte(rnd.Intn(256))
+	}
+
+	return img
+}
+
+func Test_decode_orientation(t *testing.T) {
+	src, err := encodeJPEG(halvesImage(40, 20), 100, exifWithOrientation(6))
+	require.NoError(t, err)
+
+	img, err := decode(src.Bytes())
+	require.NoError(t, err)
+
+	// Orientation 6 is rotated 90 CW on display: left half goes to the top.
+	assert.Equal(t, image.Pt(20, 40), img.Bounds().Size())
+	assertColor(t, red, img.At(10, 5))
+	assertColor(t, blue, img.At(10, 35))
+}
+
+func TestPipeline_Process_Metadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip bool
+	}{
+		{
+			name:  "strip",
+			strip: true,
+		},
+		{
+			name:  "keep",
+			strip: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := encodeJPEG(halvesImage(400, 200), 100, exifWithOrientation(6))
+			require.NoError(t, err)
+
+			p := DefaultPipeline(TypeFeedPhoto)
+			p.Encoding.StripMetadata = tt.strip
+
+			got, err := p.Process(src)
+			require.NoError(t, err)
+
+			exif := exifSegment(readAll(t, got))
+			if tt.strip {
+				assert.Nil(t, exif)
+
+				return
+			}
+
+			assert.Equal(t, uint16(orientationUpper), orientationOf(t, exif))
+		})
+	}
+}
+
+func TestEncoding_encode(t *testing.T) {
+	img := noiseImage(200, 200)
+
+	best, err := encodeJPEG(img, 95, nil)
+	require.NoError(t, err)
+
+	worst, err := encodeJPEG(img, minQuality, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		maxSize int
+		want    func(t *testing.T, size int)
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no limit",
+			maxSize: 0,
+			want: func(t *testing.T, size int) {
+				assert.Equal(t, best.Len(), size)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "quality lowered",
+			maxSize: (best.Len() + worst.Len()) / 2,
+			want: func(t *testing.T, size int) {
+				assert.Less(t, size, best.Len())
+				assert.LessOrEqual(t, size, (best.Len()+worst.Len())/2)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "too large",
+			maxSize: worst.Len() - 1,
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrFileTooLarge)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encoding{Quality: 95, MaxSize: tt.maxSize}.encode(img, nil)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			tt.want(t, len(readAll(t, got)))
+		})
+	}
+}
+
+func TestEncoding_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		enc     Encoding
+		wantErr bool
+	}{
+		{
+			name:    "default",
+			enc:     DefaultEncoding(),
+			wantErr: false,
+		},
+		{
+			name:    "zero quality",
+			enc:     Encoding{Quality: 0},
+			wantErr: true,
+		},
+		{
+			name:    "quality over 100",
+			enc:     Encoding{Quality: 101},
+			wantErr: true,
+		},
+		{
+			name:    "negative max size",
+			enc:     Encoding{Quality: 80, MaxSize: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.enc.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_exifSegment(t *testing.T) {
+	jpg, err := encodeJPEG(halvesImage(4, 4), 90, nil)
+	require.NoError(t, err)
+
+	assert.Nil(t, exifSegment(jpg.Bytes()))
+	assert.Nil(t, exifSegment([]byte("not a jpeg")))
+}
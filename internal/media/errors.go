@@ -0,0 +1,36 @@
+package media
+
+import (
+	"errors"
+)
+
+var (
+	// ErrFilesNum returned when number of files doesn't fit the media type.
+	ErrFilesNum = errors.New("invalid number of files")
+	// ErrCaptionTooLong returned when caption with hashtags exceeds instagram limit.
+	ErrCaptionTooLong = errors.New("caption is too long")
+	// ErrTooManyHashtags returned when post has more hashtags than instagram allows.
+	ErrTooManyHashtags = errors.New("too many hashtags")
+	// ErrInvalidHashtag returned when hashtag contains not allowed characters.
+	ErrInvalidHashtag = errors.New("invalid hashtag")
+	// ErrTooManyUserTags returned when post has more user tags than instagram allows.
+	ErrTooManyUserTags = errors.New("too many user tags")
+	// ErrInvalidUserTag returned when user tag could not be parsed or placed.
+	ErrInvalidUserTag = errors.New("invalid user tag")
+	// ErrStoryPostOptions returned when post options are set for a story.
+	ErrStoryPostOptions = errors.New("caption, hashtags, tags, location and post toggles are not supported for stories")
+	// ErrInvalidFrame returned when frame settings are invalid.
+	ErrInvalidFrame = errors.New("invalid frame")
+	// ErrInvalidOverlay returned when watermark or text overlay settings are invalid.
+	ErrInvalidOverlay = errors.New("invalid overlay")
+	// ErrInvalidEncoding returned when encoding settings are invalid.
+	ErrInvalidEncoding = errors.New("invalid encoding")
+	// ErrFileTooLarge returned when encoded photo doesn't fit size target even at minimal quality.
+	ErrFileTooLarge = errors.New("file is too large")
+	// ErrUnsupportedFormat returned when photo format could not be decoded.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	// ErrInvalidManifest returned when directory manifest item is invalid.
+	ErrInvalidManifest = errors.New("invalid manifest")
+	// ErrManifestFormat returned when manifest file format is not supported.
+	ErrManifestFormat = errors.New("unsupported manifest format, use json or yaml")
+)
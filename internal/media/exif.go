@@ -0,0 +1,100 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	markerSOI  = 0xD8
+	markerAPP1 = 0xE1
+	markerSOS  = 0xDA
+
+	tagOrientation   = 0x0112
+	orientationUpper = 1
+	maxSegmentLen    = 0xFFFF - 2
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifSegment returns copy of JPEG EXIF segment payload with orientation reset to normal,
+// as decoded image is already rotated. Returns nil when there is no EXIF or it is malformed.
+func exifSegment(content []byte) []byte {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != markerSOI {
+		return nil
+	}
+
+	for i := 2; i+4 <= len(content); {
+		if content[i] != 0xFF {
+			return nil
+		}
+
+		marker := content[i+1]
+		if marker == markerSOS {
+			return nil
+		}
+
+		size := int(binary.BigEndian.Uint16(content[i+2:]))
+		if size < 2 || i+2+size > len(content) {
+			return nil
+		}
+
+		payload := content[i+4 : i+2+size]
+
+		if marker == markerAPP1 && bytes.HasPrefix(payload, exifHeader) {
+			seg := bytes.Clone(payload)
+			if !resetOrientation(seg[len(exifHeader):]) || len(seg) > maxSegmentLen {
+				return nil
+			}
+
+			return seg
+		}
+
+		i += 2 + size
+	}
+
+	return nil
+}
+
+// resetOrientation sets orientation tag of TIFF IFD0 to normal, reports whether TIFF structure is valid.
+func resetOrientation(tiff []byte) bool {
+	const (
+		headerLen = 8
+		entryLen  = 12
+	)
+
+	if len(tiff) < headerLen {
+		return false
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return false
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < headerLen || ifd+2 > len(tiff) {
+		return false
+	}
+
+	n := int(order.Uint16(tiff[ifd:]))
+
+	for i := 0; i < n; i++ {
+		e := ifd + 2 + i*entryLen
+		if e+entryLen > len(tiff) {
+			return false
+		}
+
+		if order.Uint16(tiff[e:]) == tagOrientation {
+			order.PutUint16(tiff[e+8:], orientationUpper)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,52 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	// Decoders registration, all of them are pure Go.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// SupportedFormats lists photo formats that could be processed.
+var SupportedFormats = []string{"jpeg", "png", "gif", "webp", "bmp", "tiff"}
+
+// detectFormat returns registered format name of the image.
+func detectFormat(content []byte) (string, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			// There is no way to implement heif decoder without C or external tools usage.
+			return "", fmt.Errorf("%w, supported formats: %s", ErrUnsupportedFormat, strings.Join(SupportedFormats, ", "))
+		}
+
+		return "", fmt.Errorf("decode config: %w", err)
+	}
+
+	return format, nil
+}
+
+// flatten draws image with transparency over the solid colour, opaque images are returned as is.
+func flatten(img image.Image, c color.Color) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+
+	return dst
+}
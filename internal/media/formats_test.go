@@ -0,0 +1,151 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+func encoded(tb testing.TB, enc func(w io.Writer, img image.Image) error) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+
+	require.NoError(tb, enc(&buf, halvesImage(40, 20)))
+
+	return buf.Bytes()
+}
+
+func fromFile(tb testing.TB, path string) []byte {
+	tb.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(tb, err)
+
+	return content
+}
+
+func Test_decode_formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content func(tb testing.TB) []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "jpeg",
+			content: func(tb testing.TB) []byte {
+				return fromFile(tb, filepath.Join("testdata", "400x400.jpg"))
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "png",
+			content: func(tb testing.TB) []byte {
+				return fromFile(tb, filepath.Join("testdata", "tavern.png"))
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "webp",
+			content: func(tb testing.TB) []byte {
+				return fromFile(tb, filepath.Join("testdata", "sample.webp"))
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "bmp",
+			content: func(tb testing.TB) []byte {
+				return encoded(tb, bmp.Encode)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "tiff",
+			content: func(tb testing.TB) []byte {
+				return encoded(tb, func(w io.Writer, img image.Image) error {
+					return tiff.Encode(w, img, nil)
+				})
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "gif",
+			content: func(tb testing.TB) []byte {
+				return encoded(tb, func(w io.Writer, img image.Image) error {
+					return gif.Encode(w, img, nil)
+				})
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "heic",
+			content: func(tb testing.TB) []byte {
+				return fromFile(tb, filepath.Join("testdata", "sample1.heic"))
+			},
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrUnsupportedFormat) &&
+					assert.ErrorContains(t, err, "jpeg, png, gif, webp, bmp, tiff")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := decode(tt.content(t))
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			assert.False(t, img.Bounds().Empty())
+		})
+	}
+}
+
+func Test_flatten(t *testing.T) {
+	transparent := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	transparent.Set(0, 0, red)
+
+	got := flatten(transparent, blue)
+
+	assertColor(t, red, got.At(0, 0))
+	assertColor(t, blue, got.At(3, 3))
+
+	opaque := halvesImage(4, 4)
+
+	assert.Same(t, opaque, flatten(opaque, blue))
+}
+
+func TestPipeline_Process_PNGAlpha(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	src.Set(50, 50, color.NRGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, src))
+
+	p := DefaultPipeline(TypeFeedPhoto)
+	p.Frame.Fill = FillBlur
+	p.Frame.Color = blue
+
+	got, err := p.Process(&buf)
+	require.NoError(t, err)
+
+	img, err := decode(readAll(t, got))
+	require.NoError(t, err)
+
+	// Transparent area is flattened onto the frame colour instead of black.
+	assertColor(t, blue, img.At(540, 200))
+}
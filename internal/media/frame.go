@@ -0,0 +1,246 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+//go:generate stringer -type=Aspect -trimprefix=Aspect -linecomment
+
+// Aspect represents target canvas format.
+type Aspect uint
+
+const (
+	// AspectUndefined represents undefined aspect.
+	AspectUndefined Aspect = iota // undefined
+	// AspectSquare is 1:1 feed format.
+	AspectSquare // square
+	// AspectPortrait is 4:5 feed format.
+	AspectPortrait // portrait
+	// AspectLandscape is 1.91:1 feed format.
+	AspectLandscape // landscape
+	// AspectStory is 9:16 story format.
+	AspectStory // story
+
+	// aspectSentinel should be always last, marks boundary of valid values.
+	aspectSentinel // sentinel
+)
+
+// Valid checks if Aspect value is in valid boundaries.
+func (a Aspect) Valid() bool {
+	return a > AspectUndefined && a < aspectSentinel
+}
+
+// Size returns canvas size in pixels.
+func (a Aspect) Size() (int, int) {
+	const w = 1080
+
+	switch a {
+	case AspectSquare:
+		return w, w
+	case AspectPortrait:
+		return w, 1350
+	case AspectLandscape:
+		return w, 566
+	case AspectStory:
+		return w, 1920
+	default:
+		return 0, 0
+	}
+}
+
+// ParseAspect parses Aspect from string.
+func ParseAspect(v string) (Aspect, error) {
+	for a := AspectUndefined + 1; a < aspectSentinel; a++ {
+		if strings.EqualFold(v, a.String()) {
+			return a, nil
+		}
+	}
+
+	return AspectUndefined, fmt.Errorf("unknown Aspect (%s)", v)
+}
+
+//go:generate stringer -type=Fill -trimprefix=Fill -linecomment
+
+// Fill represents how image is placed on the canvas.
+type Fill uint
+
+const (
+	// FillUndefined represents undefined fill.
+	FillUndefined Fill = iota // undefined
+	// FillSolid fits image into the canvas filled with solid colour.
+	FillSolid // solid
+	// FillBlur fits image into the canvas filled with blurred copy of the image.
+	FillBlur // blur
+	// FillCrop crops image to fill the whole canvas, keeping the focal point in the frame.
+	FillCrop // crop
+
+	// fillSentinel should be always last, marks boundary of valid values.
+	fillSentinel // sentinel
+)
+
+// Valid checks if Fill value is in valid boundaries.
+func (f Fill) Valid() bool {
+	return f > FillUndefined && f < fillSentinel
+}
+
+// ParseFill parses Fill from string.
+func ParseFill(v string) (Fill, error) {
+	for f := FillUndefined + 1; f < fillSentinel; f++ {
+		if strings.EqualFold(v, f.String()) {
+			return f, nil
+		}
+	}
+
+	return FillUndefined, fmt.Errorf("unknown Fill (%s)", v)
+}
+
+// Frame describes how image is placed on the canvas.
+type Frame struct {
+	Aspect Aspect
+	Fill   Fill
+	// Border is a margin in pixels between the image and canvas edges.
+	// It is filled with Color, except blur fill where blurred background is visible.
+	Border int
+	// Color is a canvas and border colour.
+	Color color.Color
+	// FocusX and FocusY are relative focal point for crop fill, from 0 to 1.
+	FocusX float64
+	FocusY float64
+}
+
+const (
+	defaultBorder = 50
+	blurSigma     = 30
+)
+
+// DefaultFrame returns frame used for the media type: image fit into white canvas with borders.
+func DefaultFrame(mt Type) Frame {
+	a := AspectSquare
+	if mt.IsStory() {
+		a = AspectStory
+	}
+
+	return Frame{
+		Aspect: a,
+		Fill:   FillSolid,
+		Border: defaultBorder,
+		Color:  color.White,
+		FocusX: 0.5,
+		FocusY: 0.5,
+	}
+}
+
+// Validate checks frame values. Stories take only story aspect, feed posts take any other.
+func (f Frame) Validate(mt Type) error {
+	if !f.Aspect.Valid() {
+		return fmt.Errorf("aspect %s: %w", f.Aspect.String(), ErrInvalidFrame)
+	}
+
+	if mt.IsStory() != (f.Aspect == AspectStory) {
+		return fmt.Errorf("aspect %s is not supported for %s: %w", f.Aspect.String(), mt.String(), ErrInvalidFrame)
+	}
+
+	if !f.Fill.Valid() {
+		return fmt.Errorf("fill %s: %w", f.Fill.String(), ErrInvalidFrame)
+	}
+
+	w, h := f.Aspect.Size()
+
+	if f.Border < 0 || 2*f.Border >= min(w, h) {
+		return fmt.Errorf("border %d doesn't fit %dx%d canvas: %w", f.Border, w, h, ErrInvalidFrame)
+	}
+
+	if f.Color == nil {
+		return fmt.Errorf("color is not set: %w", ErrInvalidFrame)
+	}
+
+	if f.FocusX < 0 || f.FocusX > 1 || f.FocusY < 0 || f.FocusY > 1 {
+		return fmt.Errorf("focal point should be from 0 to 1: %w", ErrInvalidFrame)
+	}
+
+	return nil
+}
+
+// Render places image on the frame canvas, transparent image is flattened onto the frame colour.
+func (f Frame) Render(img image.Image) image.Image {
+	w, h := f.Aspect.Size()
+
+	img = flatten(img, f.Color)
+
+	var bg image.Image
+
+	switch f.Fill {
+	case FillBlur:
+		bg = imaging.Blur(imaging.Fill(img, w, h, imaging.Center, imaging.Linear), blurSigma)
+	default:
+		bg = imaging.New(w, h, f.Color)
+	}
+
+	iw, ih := w-2*f.Border, h-2*f.Border
+
+	var fg image.Image
+
+	switch f.Fill {
+	case FillCrop:
+		fg = cropFill(img, iw, ih, f.FocusX, f.FocusY)
+	default:
+		fg = imaging.Fit(img, iw, ih, imaging.CatmullRom)
+	}
+
+	b := fg.Bounds()
+
+	return imaging.Overlay(bg, fg, image.Pt((w-b.Dx())/2, (h-b.Dy())/2), 1.0)
+}
+
+// cropFill scales image to cover w x h and crops it around the relative focal point.
+func cropFill(img image.Image, w, h int, fx, fy float64) image.Image {
+	b := img.Bounds()
+
+	scale := math.Max(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+
+	sw := max(w, int(math.Ceil(float64(b.Dx())*scale)))
+	sh := max(h, int(math.Ceil(float64(b.Dy())*scale)))
+
+	scaled := imaging.Resize(img, sw, sh, imaging.CatmullRom)
+
+	x := clamp(int(fx*float64(sw))-w/2, 0, sw-w)
+	y := clamp(int(fy*float64(sh))-h/2, 0, sh-h)
+
+	return imaging.Crop(scaled, image.Rect(x, y, x+w, y+h))
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// ParseColor parses colour in hex format #RRGGBB or RRGGBB, white and black names are also accepted.
+func ParseColor(v string) (color.Color, error) {
+	switch strings.ToLower(v) {
+	case "white":
+		return color.White, nil
+	case "black":
+		return color.Black, nil
+	}
+
+	hex := strings.TrimPrefix(v, "#")
+
+	const hexLen = 6
+
+	if len(hex) != hexLen {
+		return nil, fmt.Errorf("color [%s] should be #RRGGBB: %w", v, ErrInvalidFrame)
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("color [%s] should be #RRGGBB: %w", v, ErrInvalidFrame)
+	}
+
+	return color.NRGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: math.MaxUint8}, nil
+}
@@ -0,0 +1,170 @@
+package media
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.NRGBA{R: 255, A: 255}
+	blue = color.NRGBA{B: 255, A: 255}
+)
+
+// halvesImage returns image with red left half and blue right half.
+func halvesImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := red
+			if x >= w/2 {
+				c = blue
+			}
+
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func assertColor(t testing.TB, want color.NRGBA, got color.Color) {
+	t.Helper()
+
+	c := color.NRGBAModel.Convert(got).(color.NRGBA)
+
+	const delta = 10
+
+	assert.InDelta(t, want.R, c.R, delta, "red")
+	assert.InDelta(t, want.G, c.G, delta, "green")
+	assert.InDelta(t, want.B, c.B, delta, "blue")
+}
+
+func TestFrame_Render(t *testing.T) {
+	src := halvesImage(400, 200)
+
+	green := color.NRGBA{G: 255, A: 255}
+
+	tests := []struct {
+		name  string
+		frame Frame
+		check func(t testing.TB, img image.Image)
+	}{
+		{
+			name:  "solid",
+			frame: Frame{Aspect: AspectSquare, Fill: FillSolid, Border: 40, Color: green},
+			check: func(t testing.TB, img image.Image) {
+				assertColor(t, green, img.At(10, 540))
+				assertColor(t, green, img.At(540, 10))
+				// small image is not upscaled, so it is in the center.
+				assertColor(t, green, img.At(300, 540))
+				assertColor(t, red, img.At(400, 540))
+				assertColor(t, blue, img.At(700, 540))
+			},
+		},
+		{
+			name:  "blur",
+			frame: Frame{Aspect: AspectPortrait, Fill: FillBlur, Border: 0, Color: color.White},
+			check: func(t testing.TB, img image.Image) {
+				// background is the image blurred, not a canvas colour.
+				c := color.NRGBAModel.Convert(img.At(10, 10)).(color.NRGBA)
+				assert.Greater(t, c.R, c.G)
+				assertColor(t, red, img.At(400, 675))
+			},
+		},
+		{
+			name:  "crop to the left",
+			frame: Frame{Aspect: AspectPortrait, Fill: FillCrop, Border: 0, Color: color.White, FocusX: 0},
+			check: func(t testing.TB, img image.Image) {
+				assertColor(t, red, img.At(0, 0))
+				assertColor(t, red, img.At(1079, 1349))
+			},
+		},
+		{
+			name:  "crop to the right with border",
+			frame: Frame{Aspect: AspectStory, Fill: FillCrop, Border: 20, Color: green, FocusX: 1, FocusY: 0.5},
+			check: func(t testing.TB, img image.Image) {
+				assertColor(t, green, img.At(5, 5))
+				assertColor(t, blue, img.At(30, 30))
+				assertColor(t, blue, img.At(1050, 1890))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.frame.Render(src)
+
+			w, h := tt.frame.Aspect.Size()
+			assert.Equal(t, image.Rect(0, 0, w, h), got.Bounds())
+
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestFrame_Validate(t *testing.T) {
+	valid := DefaultFrame(TypeFeedPhoto)
+
+	tests := []struct {
+		name    string
+		mutate  func(f *Frame)
+		mt      Type
+		wantErr bool
+	}{
+		{name: "default feed", mutate: func(*Frame) {}, mt: TypeFeedPhoto},
+		{name: "portrait feed", mutate: func(f *Frame) { f.Aspect = AspectPortrait }, mt: TypeCarousel},
+		{name: "story aspect for feed", mutate: func(f *Frame) { f.Aspect = AspectStory }, mt: TypeFeedPhoto, wantErr: true},
+		{name: "feed aspect for story", mutate: func(*Frame) {}, mt: TypeStoryPhoto, wantErr: true},
+		{name: "no fill", mutate: func(f *Frame) { f.Fill = FillUndefined }, mt: TypeFeedPhoto, wantErr: true},
+		{name: "huge border", mutate: func(f *Frame) { f.Border = 540 }, mt: TypeFeedPhoto, wantErr: true},
+		{name: "focus out of image", mutate: func(f *Frame) { f.FocusY = 1.1 }, mt: TypeFeedPhoto, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			f := valid
+			tt.mutate(&f)
+
+			err := f.Validate(tt.mt)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFrame)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	got, err := ParseColor("#FF8000")
+	require.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, G: 128, B: 0, A: 255}, got)
+
+	got, err = ParseColor("black")
+	require.NoError(t, err)
+	assert.Equal(t, color.Black, got)
+
+	_, err = ParseColor("#FFF")
+	require.ErrorIs(t, err, ErrInvalidFrame)
+
+	_, err = ParseColor("GGGGGG")
+	require.ErrorIs(t, err, ErrInvalidFrame)
+}
+
+func TestParseAspect(t *testing.T) {
+	got, err := ParseAspect("Portrait")
+	require.NoError(t, err)
+	assert.Equal(t, AspectPortrait, got)
+
+	_, err = ParseAspect("wide")
+	require.Error(t, err)
+}
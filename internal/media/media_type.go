@@ -14,6 +14,14 @@
 
 	// TypeStoryPhoto represents story photo media.
 	TypeStoryPhoto // story_photo
+	// TypeFeedPhoto represents single photo feed post.
+	TypeFeedPhoto // feed_photo
+	// TypeCarousel represents feed post with multiple photos (album).
+	TypeCarousel // carousel
+	// TypeVideo represents single video feed post.
+	TypeVideo // video
+	// TypeStoryVideo represents story video media, multiple videos are posted as several stories.
+	TypeStoryVideo // story_video
 
 	// typeSentinel should be always last, marks boundary of valid values.
 	typeSentinel // sentinel
@@ -37,6 +45,14 @@ func Parse(v string) (Type, error) {
 	switch {
 	case strings.EqualFold(v, TypeStoryPhoto.String()):
 		mt = TypeStoryPhoto
+	case strings.EqualFold(v, TypeFeedPhoto.String()):
+		mt = TypeFeedPhoto
+	case strings.EqualFold(v, TypeCarousel.String()):
+		mt = TypeCarousel
+	case strings.EqualFold(v, TypeVideo.String()):
+		mt = TypeVideo
+	case strings.EqualFold(v, TypeStoryVideo.String()):
+		mt = TypeStoryVideo
 	default:
 		mt = TypeUndefined
 
@@ -45,3 +61,35 @@ func Parse(v string) (Type, error) {
 
 	return mt, err
 }
+
+// IsStory reports whether media is posted as a story.
+func (t Type) IsStory() bool {
+	return t == TypeStoryPhoto || t == TypeStoryVideo
+}
+
+// IsVideo reports whether media files are videos.
+func (t Type) IsVideo() bool {
+	return t == TypeVideo || t == TypeStoryVideo
+}
+
+// Instagram limits number of items in the album.
+const maxAlbumFiles = 10
+
+// CheckFilesNum checks that number of files could be uploaded as the media type.
+// Carousel takes from 2 to 10 files, story video up to 10 files, other types exactly one.
+func (t Type) CheckFilesNum(n int) error {
+	minimum, maximum := 1, 1
+
+	switch t {
+	case TypeCarousel:
+		minimum, maximum = 2, maxAlbumFiles
+	case TypeStoryVideo:
+		maximum = maxAlbumFiles
+	}
+
+	if n < minimum || n > maximum {
+		return fmt.Errorf("%s takes from %d to %d files, got %d: %w", t.String(), minimum, maximum, n, ErrFilesNum)
+	}
+
+	return nil
+}
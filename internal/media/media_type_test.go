@@ -0,0 +1,64 @@
+package media
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    Type
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "story photo", v: "story_photo", want: TypeStoryPhoto, wantErr: assert.NoError},
+		{name: "feed photo", v: "FEED_PHOTO", want: TypeFeedPhoto, wantErr: assert.NoError},
+		{name: "carousel", v: "carousel", want: TypeCarousel, wantErr: assert.NoError},
+		{name: "video", v: "video", want: TypeVideo, wantErr: assert.NoError},
+		{name: "story video", v: "story_video", want: TypeStoryVideo, wantErr: assert.NoError},
+		{name: "unknown", v: "reel", want: TypeUndefined, wantErr: assert.Error},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.v)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestType_CheckFilesNum(t *testing.T) {
+	tests := []struct {
+		name    string
+		mt      Type
+		n       int
+		wantErr bool
+	}{
+		{name: "feed photo single", mt: TypeFeedPhoto, n: 1, wantErr: false},
+		{name: "feed photo multiple", mt: TypeFeedPhoto, n: 2, wantErr: true},
+		{name: "story photo none", mt: TypeStoryPhoto, n: 0, wantErr: true},
+		{name: "carousel single", mt: TypeCarousel, n: 1, wantErr: true},
+		{name: "carousel max", mt: TypeCarousel, n: 10, wantErr: false},
+		{name: "carousel over max", mt: TypeCarousel, n: 11, wantErr: true},
+		{name: "story video multiple", mt: TypeStoryVideo, n: 3, wantErr: false},
+		{name: "video multiple", mt: TypeVideo, n: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mt.CheckFilesNum(tt.n)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrFilesNum)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
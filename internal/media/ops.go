@@ -3,11 +3,8 @@
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"image"
-	"image/color"
-	"image/jpeg"
 	"io"
 	"net/http"
 
@@ -15,87 +12,86 @@
 	log "github.com/obalunenko/logger"
 )
 
-// AddBorders add white borders to the image.
-func AddBorders(r io.Reader, mt Type) (io.Reader, error) {
-	if !mt.Valid() {
-		return nil, fmt.Errorf("unsupported media type[%s]", mt.String())
-	}
-
-	var (
-		w, h int
-	)
+// Pipeline describes how photos are processed before upload.
+type Pipeline struct {
+	Frame Frame
+	// Overlays are drawn over the framed photo.
+	Overlays Overlays
+	Encoding Encoding
+}
 
-	if mt == TypeStoryPhoto {
-		w = 1080
-		h = 1920
+// DefaultPipeline returns pipeline used for the media type.
+func DefaultPipeline(mt Type) Pipeline {
+	return Pipeline{
+		Frame:    DefaultFrame(mt),
+		Encoding: DefaultEncoding(),
 	}
-
-	return addBorders(r, w, h)
 }
 
-func addBorders(r io.Reader, w, h int) (io.Reader, error) {
-	img, err := decode(r)
-	if err != nil {
-		return nil, err
+// Validate checks pipeline settings for the media type.
+func (p Pipeline) Validate(mt Type) error {
+	if !mt.Valid() || mt.IsVideo() {
+		return fmt.Errorf("unsupported media type[%s]", mt.String())
 	}
 
-	const (
-		b = 100
-	)
+	if err := p.Frame.Validate(mt); err != nil {
+		return err
+	}
 
-	img = resizeImage(img, w-b, h-b)
-	img = resizeImage(img, w, h)
+	if err := p.Overlays.Validate(); err != nil {
+		return err
+	}
 
-	return encode(img)
+	return p.Encoding.Validate()
 }
 
-func decode(r io.Reader) (image.Image, error) {
-	// get file type
+// Process decodes photo honoring EXIF orientation, places it on the frame, draws overlays and encodes result to JPEG.
+func (p Pipeline) Process(r io.Reader) (io.Reader, error) {
 	content, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
-	ct, err := getFileContentType(bytes.NewReader(content))
+	img, err := decode(content)
 	if err != nil {
-		return nil, fmt.Errorf("get file content type: %w", err)
+		return nil, err
 	}
 
-	log.WithFields(context.TODO(), log.Fields{
-		"file_type": ct,
-	}).Debug("Media file")
-
-	if ct == "application/octet-stream" {
-		// There is no way to implement heif decoder without C or external tools usage.
-		return nil, errors.New("unsupported format")
+	var exif []byte
+	if !p.Encoding.StripMetadata {
+		exif = exifSegment(content)
 	}
 
-	img, _, err := image.Decode(bytes.NewReader(content))
+	img, err = p.Overlays.Apply(p.Frame.Render(img))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("apply overlays: %w", err)
 	}
 
-	return img, nil
+	return p.Encoding.encode(img, exif)
 }
 
-func encode(img image.Image) (io.Reader, error) {
-	buf := new(bytes.Buffer)
-
-	if err := jpeg.Encode(buf, img, nil); err != nil {
-		return nil, err
+func decode(content []byte) (image.Image, error) {
+	ct, err := getFileContentType(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("get file content type: %w", err)
 	}
 
-	return buf, nil
-}
+	format, err := detectFormat(content)
+	if err != nil {
+		return nil, fmt.Errorf("file type %s: %w", ct, err)
+	}
 
-func resizeImage(src image.Image, w, h int) image.Image {
-	dst := imaging.New(w, h, color.White)
+	log.WithFields(context.TODO(), log.Fields{
+		"file_type": ct,
+		"format":    format,
+	}).Debug("Media file")
 
-	tmp := imaging.Fit(src, w, h, imaging.CatmullRom)
-	iw := tmp.Rect.Bounds().Max.X - tmp.Rect.Bounds().Min.X
-	ih := tmp.Rect.Bounds().Max.Y - tmp.Rect.Bounds().Min.Y
+	img, err := imaging.Decode(bytes.NewReader(content), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, err
+	}
 
-	return imaging.Overlay(dst, tmp, image.Pt(w/2-iw/2, h/2-ih/2), 1.0)
+	return img, nil
 }
 
 func getFileContentType(f io.Reader) (string, error) {
@@ -19,7 +19,7 @@ type file struct {
 	path string
 }
 
-func Test_addBorders(t *testing.T) {
+func TestPipeline_Process(t *testing.T) {
 	if getenv.EnvOrDefault("CI", false) {
 		t.Skip("Doesn't work on CI")
 	}
@@ -29,8 +29,7 @@ type file struct {
 	}
 
 	type args struct {
-		w     int
-		h     int
+		mt    Type
 		input file
 	}
 
@@ -43,8 +42,7 @@ type args struct {
 		{
 			name: "400x400_square_smaller",
 			args: args{
-				w: 1080,
-				h: 1920,
+				mt: TypeStoryPhoto,
 				input: file{
 					path: filepath.Join("testdata", "400x400.jpg"),
 				},
@@ -58,8 +56,7 @@ type args struct {
 		{
 			name: "1200x2600_rect_bigger",
 			args: args{
-				w: 1080,
-				h: 1920,
+				mt: TypeStoryPhoto,
 				input: file{
 					path: filepath.Join("testdata", "1200x2600.jpg"),
 				},
@@ -72,8 +69,7 @@ type args struct {
 		{
 			name: "1080x1090_rect_smaller",
 			args: args{
-				w: 1080,
-				h: 1920,
+				mt: TypeStoryPhoto,
 				input: file{
 					path: filepath.Join("testdata", "1080x1090.jpg"),
 				},
@@ -86,8 +82,7 @@ type args struct {
 		{
 			name: "1080x1920_rect_exact",
 			args: args{
-				w: 1080,
-				h: 1920,
+				mt: TypeStoryPhoto,
 				input: file{
 					path: filepath.Join("testdata", "1080x1920.jpg"),
 				},
@@ -100,8 +95,7 @@ type args struct {
 		{
 			name: "1440x960_heic",
 			args: args{
-				w: 1080,
-				h: 1920,
+				mt: TypeStoryPhoto,
 				input: file{
 					path: filepath.Join("testdata", "sample1.heic"),
 				},
@@ -115,7 +109,7 @@ type args struct {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := addBorders(getReaderFromPath(t, tt.args.input.path), tt.args.w, tt.args.h)
+			got, err := DefaultPipeline(tt.args.mt).Process(getReaderFromPath(t, tt.args.input.path))
 			if !tt.wantErr(t, err) {
 				return
 			}
@@ -140,13 +134,22 @@ func getReaderFromPath(tb testing.TB, path string) io.Reader {
 	return bytes.NewReader(content)
 }
 
+func readAll(tb testing.TB, r io.Reader) []byte {
+	tb.Helper()
+
+	content, err := io.ReadAll(r)
+	require.NoError(tb, err)
+
+	return content
+}
+
 func diffImageReaders(tb testing.TB, want, actual io.Reader) {
 	tb.Helper()
 
-	wantimg, err := decode(want)
+	wantimg, err := decode(readAll(tb, want))
 	require.NoError(tb, err)
 
-	actimg, err := decode(actual)
+	actimg, err := decode(readAll(tb, actual))
 	require.NoError(tb, err)
 
 	var eq bool
@@ -164,7 +167,7 @@ func diffImageReaders(tb testing.TB, want, actual io.Reader) {
 			require.NoError(tb, f.Close())
 		})
 
-		r, err := encode(d)
+		r, err := Encoding{Quality: defaultQuality}.encode(d, nil)
 		require.NoError(tb, err)
 
 		buf := new(bytes.Buffer)
@@ -0,0 +1,229 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+//go:generate stringer -type=Position -trimprefix=Position -linecomment
+
+// Position represents where overlay is placed on the photo.
+type Position uint
+
+const (
+	// PositionUndefined represents undefined position.
+	PositionUndefined Position = iota // undefined
+	// PositionTopLeft is the top left corner.
+	PositionTopLeft // top_left
+	// PositionTopRight is the top right corner.
+	PositionTopRight // top_right
+	// PositionBottomLeft is the bottom left corner.
+	PositionBottomLeft // bottom_left
+	// PositionBottomRight is the bottom right corner.
+	PositionBottomRight // bottom_right
+	// PositionCenter is the photo center.
+	PositionCenter // center
+
+	// positionSentinel should be always last, marks boundary of valid values.
+	positionSentinel // sentinel
+)
+
+// Valid checks if Position value is in valid boundaries.
+func (p Position) Valid() bool {
+	return p > PositionUndefined && p < positionSentinel
+}
+
+// ParsePosition parses Position from string.
+func ParsePosition(v string) (Position, error) {
+	for p := PositionUndefined + 1; p < positionSentinel; p++ {
+		if strings.EqualFold(v, p.String()) {
+			return p, nil
+		}
+	}
+
+	return PositionUndefined, fmt.Errorf("unknown Position (%s)", v)
+}
+
+// point returns top left corner of w x h overlay placed on the canvas with margin from its edges.
+func (p Position) point(canvas image.Rectangle, w, h, margin int) image.Point {
+	cw, ch := canvas.Dx(), canvas.Dy()
+
+	switch p {
+	case PositionTopLeft:
+		return image.Pt(margin, margin)
+	case PositionTopRight:
+		return image.Pt(cw-w-margin, margin)
+	case PositionBottomLeft:
+		return image.Pt(margin, ch-h-margin)
+	case PositionBottomRight:
+		return image.Pt(cw-w-margin, ch-h-margin)
+	default:
+		return image.Pt((cw-w)/2, (ch-h)/2)
+	}
+}
+
+// Watermark is an image, e.g. logo, drawn over the photo.
+type Watermark struct {
+	Image    image.Image
+	Position Position
+	// Scale is a watermark width relative to the photo width, from 0 to 1.
+	Scale float64
+	// Opacity is from 0 (invisible) to 1 (opaque).
+	Opacity float64
+	// Margin is a distance in pixels from the photo edges.
+	Margin int
+}
+
+// Text is a text line drawn over the photo with bold Go font.
+type Text struct {
+	Text     string
+	Position Position
+	// Size is a font size in pixels.
+	Size  float64
+	Color color.Color
+	// Opacity is from 0 (invisible) to 1 (opaque).
+	Opacity float64
+	// Margin is a distance in pixels from the photo edges.
+	Margin int
+}
+
+// Overlays are drawn over the rendered photo: watermark first, then texts.
+type Overlays struct {
+	Watermark *Watermark
+	Texts     []Text
+}
+
+// IsZero reports whether there is nothing to draw.
+func (o Overlays) IsZero() bool {
+	return o.Watermark == nil && len(o.Texts) == 0
+}
+
+// Validate checks overlays values.
+func (o Overlays) Validate() error {
+	if w := o.Watermark; w != nil {
+		if w.Image == nil {
+			return fmt.Errorf("watermark image is not set: %w", ErrInvalidOverlay)
+		}
+
+		if !w.Position.Valid() {
+			return fmt.Errorf("watermark position %s: %w", w.Position.String(), ErrInvalidOverlay)
+		}
+
+		if w.Scale <= 0 || w.Scale > 1 || w.Opacity < 0 || w.Opacity > 1 || w.Margin < 0 {
+			return fmt.Errorf("watermark scale should be from 0 to 1, opacity from 0 to 1, margin positive: %w",
+				ErrInvalidOverlay)
+		}
+	}
+
+	for i, t := range o.Texts {
+		if strings.TrimSpace(t.Text) == "" || t.Color == nil || !t.Position.Valid() {
+			return fmt.Errorf("text %d: text, color and position should be set: %w", i+1, ErrInvalidOverlay)
+		}
+
+		if t.Size <= 0 || t.Opacity < 0 || t.Opacity > 1 || t.Margin < 0 {
+			return fmt.Errorf("text %d: size should be positive, opacity from 0 to 1, margin positive: %w",
+				i+1, ErrInvalidOverlay)
+		}
+	}
+
+	return nil
+}
+
+// Apply draws overlays over the image.
+func (o Overlays) Apply(img image.Image) (image.Image, error) {
+	if o.IsZero() {
+		return img, nil
+	}
+
+	dst := imaging.Clone(img)
+
+	if w := o.Watermark; w != nil {
+		mark := imaging.Resize(w.Image, int(w.Scale*float64(dst.Bounds().Dx())), 0, imaging.Lanczos)
+		b := mark.Bounds()
+
+		dst = imaging.Overlay(dst, mark, w.Position.point(dst.Bounds(), b.Dx(), b.Dy(), w.Margin), w.Opacity)
+	}
+
+	for i, t := range o.Texts {
+		if err := drawText(dst, t); err != nil {
+			return nil, fmt.Errorf("text %d: %w", i+1, err)
+		}
+	}
+
+	return dst, nil
+}
+
+var textFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(gobold.TTF)
+})
+
+func drawText(dst draw.Image, t Text) error {
+	f, err := textFont()
+	if err != nil {
+		return fmt.Errorf("parse font: %w", err)
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    t.Size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return fmt.Errorf("make font face: %w", err)
+	}
+
+	defer func() {
+		_ = face.Close()
+	}()
+
+	c, _ := color.NRGBAModel.Convert(t.Color).(color.NRGBA)
+	c.A = uint8(t.Opacity * 0xFF)
+
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+	}
+
+	m := face.Metrics()
+	w := d.MeasureString(t.Text).Ceil()
+	h := (m.Ascent + m.Descent).Ceil()
+
+	pt := t.Position.point(dst.Bounds(), w, h, t.Margin)
+
+	d.Dot = fixed.Point26_6{
+		X: fixed.I(pt.X),
+		Y: fixed.I(pt.Y) + m.Ascent,
+	}
+
+	d.DrawString(t.Text)
+
+	return nil
+}
+
+// LoadWatermark reads watermark image, transparency is kept.
+func LoadWatermark(path string) (image.Image, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read watermark: %w", err)
+	}
+
+	img, err := decode(content)
+	if err != nil {
+		return nil, fmt.Errorf("decode watermark: %w", err)
+	}
+
+	return img, nil
+}
@@ -0,0 +1,246 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func solidImage(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    Position
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "top left", v: "top_left", want: PositionTopLeft, wantErr: assert.NoError},
+		{name: "case insensitive", v: "Bottom_Right", want: PositionBottomRight, wantErr: assert.NoError},
+		{name: "center", v: "center", want: PositionCenter, wantErr: assert.NoError},
+		{name: "unknown", v: "middle", want: PositionUndefined, wantErr: assert.Error},
+		{name: "sentinel", v: "sentinel", want: PositionUndefined, wantErr: assert.Error},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePosition(tt.v)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPosition_point(t *testing.T) {
+	canvas := image.Rect(0, 0, 100, 50)
+
+	tests := []struct {
+		name string
+		p    Position
+		want image.Point
+	}{
+		{name: "top left", p: PositionTopLeft, want: image.Pt(5, 5)},
+		{name: "top right", p: PositionTopRight, want: image.Pt(75, 5)},
+		{name: "bottom left", p: PositionBottomLeft, want: image.Pt(5, 35)},
+		{name: "bottom right", p: PositionBottomRight, want: image.Pt(75, 35)},
+		{name: "center", p: PositionCenter, want: image.Pt(40, 20)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.p.point(canvas, 20, 10, 5))
+		})
+	}
+}
+
+func TestOverlays_Validate(t *testing.T) {
+	mark := solidImage(10, 10, red)
+
+	validErr := func(t assert.TestingT, err error, _ ...interface{}) bool {
+		return assert.ErrorIs(t, err, ErrInvalidOverlay)
+	}
+
+	tests := []struct {
+		name    string
+		o       Overlays
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "empty",
+			o:       Overlays{},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "valid",
+			o: Overlays{
+				Watermark: &Watermark{Image: mark, Position: PositionCenter, Scale: 0.5, Opacity: 1},
+				Texts:     []Text{{Text: "hi", Position: PositionTopLeft, Size: 12, Color: blue, Opacity: 0.5}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "watermark without image",
+			o:       Overlays{Watermark: &Watermark{Position: PositionCenter, Scale: 0.5, Opacity: 1}},
+			wantErr: validErr,
+		},
+		{
+			name:    "watermark scale",
+			o:       Overlays{Watermark: &Watermark{Image: mark, Position: PositionCenter, Scale: 1.5, Opacity: 1}},
+			wantErr: validErr,
+		},
+		{
+			name:    "watermark position",
+			o:       Overlays{Watermark: &Watermark{Image: mark, Scale: 0.5, Opacity: 1}},
+			wantErr: validErr,
+		},
+		{
+			name:    "empty text",
+			o:       Overlays{Texts: []Text{{Text: " ", Position: PositionTopLeft, Size: 12, Color: blue, Opacity: 1}}},
+			wantErr: validErr,
+		},
+		{
+			name:    "text opacity",
+			o:       Overlays{Texts: []Text{{Text: "hi", Position: PositionTopLeft, Size: 12, Color: blue, Opacity: 2}}},
+			wantErr: validErr,
+		},
+		{
+			name:    "text size",
+			o:       Overlays{Texts: []Text{{Text: "hi", Position: PositionTopLeft, Color: blue, Opacity: 1}}},
+			wantErr: validErr,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, tt.o.Validate())
+		})
+	}
+}
+
+func TestOverlays_Apply_Watermark(t *testing.T) {
+	src := solidImage(100, 100, blue)
+
+	o := Overlays{
+		Watermark: &Watermark{
+			Image:    solidImage(10, 10, red),
+			Position: PositionTopLeft,
+			Scale:    0.2,
+			Opacity:  1,
+			Margin:   10,
+		},
+	}
+
+	got, err := o.Apply(src)
+	require.NoError(t, err)
+
+	// Watermark is scaled to 20x20 and placed with 10px margin.
+	assertColor(t, red, got.At(20, 20))
+	assertColor(t, blue, got.At(5, 5))
+	assertColor(t, blue, got.At(35, 35))
+	// Source is not modified.
+	assertColor(t, blue, src.At(20, 20))
+
+	o.Watermark.Opacity = 0.5
+
+	got, err = o.Apply(src)
+	require.NoError(t, err)
+
+	assertColor(t, color.NRGBA{R: 128, B: 127, A: 255}, got.At(20, 20))
+}
+
+func TestOverlays_Apply_Text(t *testing.T) {
+	src := solidImage(200, 100, blue)
+
+	o := Overlays{
+		Texts: []Text{
+			{Text: "WWW", Position: PositionCenter, Size: 40, Color: red, Opacity: 1},
+		},
+	}
+
+	got, err := o.Apply(src)
+	require.NoError(t, err)
+
+	var changed int
+
+	for x := 0; x < 200; x++ {
+		for y := 0; y < 100; y++ {
+			if c := color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA); c.R > 200 && c.B < 50 {
+				changed++
+			}
+		}
+	}
+
+	assert.Positive(t, changed, "text pixels are drawn")
+	// Corners are far from the centered text.
+	assertColor(t, blue, got.At(0, 0))
+	assertColor(t, blue, got.At(199, 99))
+}
+
+func TestPipeline_Process_Overlays(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, solidImage(1080, 1080, blue)))
+
+	p := DefaultPipeline(TypeFeedPhoto)
+	p.Overlays = Overlays{
+		Watermark: &Watermark{Image: solidImage(10, 10, red), Position: PositionBottomRight, Scale: 0.1, Opacity: 1},
+	}
+
+	got, err := p.Process(&buf)
+	require.NoError(t, err)
+
+	img, err := decode(readAll(t, got))
+	require.NoError(t, err)
+
+	// Overlay is drawn after framing, in the bottom right corner of the output.
+	b := img.Bounds()
+	assertColor(t, red, img.At(b.Max.X-20, b.Max.Y-20))
+	assertColor(t, blue, img.At(b.Dx()/2, b.Dy()/2))
+}
+
+func TestLoadWatermark(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "logo.png")
+
+	var buf bytes.Buffer
+
+	logo := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	logo.Set(0, 0, red)
+
+	require.NoError(t, png.Encode(&buf, logo))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	img, err := LoadWatermark(path)
+	require.NoError(t, err)
+
+	// Transparency is kept to blend logo with the photo.
+	_, _, _, a := img.At(3, 3).RGBA()
+	assert.Zero(t, a)
+
+	_, err = LoadWatermark(filepath.Join(dir, "missing.png"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Instagram post limits.
+const (
+	maxCaptionLen = 2200
+	maxHashtags   = 30
+	maxUserTags   = 20
+)
+
+var hashtagRe = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// captionHashtagRe finds hashtags written in the caption text.
+var captionHashtagRe = regexp.MustCompile(`#[\p{L}\p{N}_]+`)
+
+// PostOptions represents post details applied on upload. Could be loaded from the manifest file.
+type PostOptions struct {
+	// Caption is post text.
+	Caption string `json:"caption" yaml:"caption" bson:"caption"`
+	// Hashtags are added to the end of the caption, leading # is optional.
+	Hashtags []string `json:"hashtags" yaml:"hashtags" bson:"hashtags"`
+	// UserTags are users tagged on the photo.
+	UserTags []UserTag `json:"user_tags" yaml:"user_tags" bson:"user_tags"`
+	// Location is a place name, the first found place is used.
+	Location string `json:"location" yaml:"location" bson:"location"`
+	// DisableComments turns off comments for the post.
+	DisableComments bool `json:"disable_comments" yaml:"disable_comments" bson:"disable_comments"`
+	// HideLikeCount hides likes and views count of the post.
+	HideLikeCount bool `json:"hide_like_count" yaml:"hide_like_count" bson:"hide_like_count"`
+}
+
+// UserTag represents user tagged on the photo.
+type UserTag struct {
+	Username string `json:"username" yaml:"username" bson:"username"`
+	// X and Y are relative tag position from the top left corner, from 0 to 1. Center is used when not set.
+	X float64 `json:"x" yaml:"x" bson:"x"`
+	Y float64 `json:"y" yaml:"y" bson:"y"`
+	// File is a number of the carousel file to tag user on, starting from 1. The first file is used when not set.
+	File int `json:"file" yaml:"file" bson:"file"`
+}
+
+// ParseUserTag parses user tag in the format username[:x:y[:file]].
+func ParseUserTag(s string) (UserTag, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+
+	tag := UserTag{
+		Username: strings.TrimPrefix(parts[0], "@"),
+	}
+
+	if tag.Username == "" || len(parts) == 2 || len(parts) > 4 {
+		return UserTag{}, fmt.Errorf("user tag [%s] should be username[:x:y[:file]]: %w", s, ErrInvalidUserTag)
+	}
+
+	if len(parts) == 1 {
+		return tag, nil
+	}
+
+	var err error
+
+	if tag.X, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return UserTag{}, fmt.Errorf("user tag [%s] x: %w", s, ErrInvalidUserTag)
+	}
+
+	if tag.Y, err = strconv.ParseFloat(parts[2], 64); err != nil {
+		return UserTag{}, fmt.Errorf("user tag [%s] y: %w", s, ErrInvalidUserTag)
+	}
+
+	if len(parts) == 4 {
+		if tag.File, err = strconv.Atoi(parts[3]); err != nil {
+			return UserTag{}, fmt.Errorf("user tag [%s] file: %w", s, ErrInvalidUserTag)
+		}
+	}
+
+	return tag, nil
+}
+
+// LoadPostOptions reads post options from the JSON or YAML manifest, format is chosen by the file extension.
+func LoadPostOptions(path string) (PostOptions, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return PostOptions{}, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var opts PostOptions
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(content, &opts)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &opts)
+	default:
+		return PostOptions{}, fmt.Errorf("manifest extension [%s]: %w", ext, ErrManifestFormat)
+	}
+
+	if err != nil {
+		return PostOptions{}, fmt.Errorf("decode manifest: %w", err)
+	}
+
+	return opts, nil
+}
+
+// FullCaption returns caption with hashtags appended.
+func (o PostOptions) FullCaption() string {
+	if len(o.Hashtags) == 0 {
+		return o.Caption
+	}
+
+	tags := make([]string, 0, len(o.Hashtags))
+
+	for _, h := range o.Hashtags {
+		tags = append(tags, "#"+strings.TrimPrefix(h, "#"))
+	}
+
+	if o.Caption == "" {
+		return strings.Join(tags, " ")
+	}
+
+	return o.Caption + "\n\n" + strings.Join(tags, " ")
+}
+
+// IsZero reports whether no options are set.
+func (o PostOptions) IsZero() bool {
+	return o.Caption == "" && len(o.Hashtags) == 0 && len(o.UserTags) == 0 && o.Location == "" &&
+		!o.DisableComments && !o.HideLikeCount
+}
+
+// Validate checks that options fit instagram limits for the media type and number of files.
+func (o PostOptions) Validate(mt Type, files int) error {
+	if mt.IsStory() {
+		if !o.IsZero() {
+			return fmt.Errorf("%s: %w", mt.String(), ErrStoryPostOptions)
+		}
+
+		return nil
+	}
+
+	for _, h := range o.Hashtags {
+		if !hashtagRe.MatchString(strings.TrimPrefix(h, "#")) {
+			return fmt.Errorf("hashtag [%s]: %w", h, ErrInvalidHashtag)
+		}
+	}
+
+	caption := o.FullCaption()
+
+	if n := utf8.RuneCountInString(caption); n > maxCaptionLen {
+		return fmt.Errorf("%d characters, max %d: %w", n, maxCaptionLen, ErrCaptionTooLong)
+	}
+
+	if n := len(captionHashtagRe.FindAllString(caption, -1)); n > maxHashtags {
+		return fmt.Errorf("%d hashtags, max %d: %w", n, maxHashtags, ErrTooManyHashtags)
+	}
+
+	if n := len(o.UserTags); n > maxUserTags {
+		return fmt.Errorf("%d user tags, max %d: %w", n, maxUserTags, ErrTooManyUserTags)
+	}
+
+	for _, t := range o.UserTags {
+		if err := t.validate(mt, files); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t UserTag) validate(mt Type, files int) error {
+	if t.Username == "" {
+		return fmt.Errorf("empty username: %w", ErrInvalidUserTag)
+	}
+
+	if t.X < 0 || t.X > 1 || t.Y < 0 || t.Y > 1 {
+		return fmt.Errorf("user tag [%s] position should be from 0 to 1: %w", t.Username, ErrInvalidUserTag)
+	}
+
+	if t.File < 0 || t.File > files || (t.File > 1 && mt != TypeCarousel) {
+		return fmt.Errorf("user tag [%s] file %d is out of files: %w", t.Username, t.File, ErrInvalidUserTag)
+	}
+
+	return nil
+}
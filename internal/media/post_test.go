@@ -0,0 +1,174 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    UserTag
+		wantErr bool
+	}{
+		{name: "username", s: "@alice", want: UserTag{Username: "alice"}},
+		{name: "position", s: "alice:0.25:0.75", want: UserTag{Username: "alice", X: 0.25, Y: 0.75}},
+		{name: "carousel file", s: "alice:0.5:0.5:2", want: UserTag{Username: "alice", X: 0.5, Y: 0.5, File: 2}},
+		{name: "only x", s: "alice:0.5", wantErr: true},
+		{name: "not a number", s: "alice:a:0.5", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserTag(tt.s)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidUserTag)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPostOptions_FullCaption(t *testing.T) {
+	assert.Equal(t, "Sunset\n\n#sea #sun", PostOptions{Caption: "Sunset", Hashtags: []string{"sea", "#sun"}}.FullCaption())
+	assert.Equal(t, "#sea", PostOptions{Hashtags: []string{"sea"}}.FullCaption())
+	assert.Equal(t, "Sunset", PostOptions{Caption: "Sunset"}.FullCaption())
+}
+
+func TestPostOptions_Validate(t *testing.T) {
+	many := make([]string, 0, maxHashtags)
+
+	for i := 0; i < maxHashtags; i++ {
+		many = append(many, "tag")
+	}
+
+	tests := []struct {
+		name    string
+		opts    PostOptions
+		mt      Type
+		files   int
+		wantErr error
+	}{
+		{
+			name: "valid",
+			opts: PostOptions{
+				Caption:         "Sunset #sea",
+				Hashtags:        []string{"sun"},
+				UserTags:        []UserTag{{Username: "alice", X: 0.5, Y: 0.5, File: 2}},
+				Location:        "Lisbon",
+				DisableComments: true,
+			},
+			mt:    TypeCarousel,
+			files: 2,
+		},
+		{
+			name:    "caption too long",
+			opts:    PostOptions{Caption: strings.Repeat("a", maxCaptionLen+1)},
+			mt:      TypeFeedPhoto,
+			files:   1,
+			wantErr: ErrCaptionTooLong,
+		},
+		{
+			name:    "hashtags in caption are counted",
+			opts:    PostOptions{Caption: "#one", Hashtags: many},
+			mt:      TypeFeedPhoto,
+			files:   1,
+			wantErr: ErrTooManyHashtags,
+		},
+		{
+			name:    "invalid hashtag",
+			opts:    PostOptions{Hashtags: []string{"two words"}},
+			mt:      TypeFeedPhoto,
+			files:   1,
+			wantErr: ErrInvalidHashtag,
+		},
+		{
+			name:    "tag position",
+			opts:    PostOptions{UserTags: []UserTag{{Username: "alice", X: 1.5}}},
+			mt:      TypeFeedPhoto,
+			files:   1,
+			wantErr: ErrInvalidUserTag,
+		},
+		{
+			name:    "tag file out of carousel",
+			opts:    PostOptions{UserTags: []UserTag{{Username: "alice", File: 3}}},
+			mt:      TypeCarousel,
+			files:   2,
+			wantErr: ErrInvalidUserTag,
+		},
+		{
+			name:    "story caption",
+			opts:    PostOptions{Caption: "hi"},
+			mt:      TypeStoryPhoto,
+			files:   1,
+			wantErr: ErrStoryPostOptions,
+		},
+		{
+			name:  "story without options",
+			opts:  PostOptions{},
+			mt:    TypeStoryVideo,
+			files: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(tt.mt, tt.files)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadPostOptions(t *testing.T) {
+	want := PostOptions{
+		Caption:       "Sunset",
+		Hashtags:      []string{"sea"},
+		UserTags:      []UserTag{{Username: "alice", X: 0.1, Y: 0.2}},
+		Location:      "Lisbon",
+		HideLikeCount: true,
+	}
+
+	files := map[string]string{
+		"post.json": `{"caption":"Sunset","hashtags":["sea"],"user_tags":[{"username":"alice","x":0.1,"y":0.2}],` +
+			`"location":"Lisbon","hide_like_count":true}`,
+		"post.yaml": "caption: Sunset\nhashtags: [sea]\nuser_tags:\n  - username: alice\n    x: 0.1\n    y: 0.2\n" +
+			"location: Lisbon\nhide_like_count: true\n",
+	}
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+
+		got, err := LoadPostOptions(p)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	p := filepath.Join(dir, "post.txt")
+	require.NoError(t, os.WriteFile(p, nil, 0o600))
+
+	_, err := LoadPostOptions(p)
+	assert.ErrorIs(t, err, ErrManifestFormat)
+}
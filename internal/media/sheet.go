@@ -0,0 +1,53 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// sheetCell is a side of the contact sheet cell, photos are fit into it.
+	sheetCell = 540
+	sheetGap  = 20
+)
+
+var sheetBackground = color.NRGBA{R: 0xEE, G: 0xEE, B: 0xEE, A: 0xFF}
+
+// ContactSheet builds JPEG with photos side by side: sources on the left and rendered ones on the right, a pair per row.
+func ContactSheet(sources, rendered []io.Reader) (io.Reader, error) {
+	if len(sources) != len(rendered) || len(sources) == 0 {
+		return nil, fmt.Errorf("sources %d and rendered %d photos: %w", len(sources), len(rendered), ErrFilesNum)
+	}
+
+	rows := len(sources)
+
+	sheet := imaging.New(2*sheetCell+3*sheetGap, rows*sheetCell+(rows+1)*sheetGap, sheetBackground)
+
+	for i := range sources {
+		for j, r := range []io.Reader{sources[i], rendered[i]} {
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return nil, fmt.Errorf("read file %d: %w", i+1, err)
+			}
+
+			img, err := decode(content)
+			if err != nil {
+				return nil, fmt.Errorf("decode file %d: %w", i+1, err)
+			}
+
+			img = imaging.Fit(flatten(img, color.White), sheetCell, sheetCell, imaging.CatmullRom)
+
+			b := img.Bounds()
+			x := sheetGap + j*(sheetCell+sheetGap) + (sheetCell-b.Dx())/2
+			y := sheetGap + i*(sheetCell+sheetGap) + (sheetCell-b.Dy())/2
+
+			sheet = imaging.Paste(sheet, img, image.Pt(x, y))
+		}
+	}
+
+	return Encoding{Quality: defaultQuality}.encode(sheet, nil)
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func solidPNG(t *testing.T, c color.Color, w, h int) io.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, imaging.New(w, h, c)))
+
+	return &buf
+}
+
+func TestContactSheet(t *testing.T) {
+	green := color.NRGBA{G: 255, A: 255}
+
+	sources := []io.Reader{solidPNG(t, red, 1000, 1000), solidPNG(t, green, 1000, 500)}
+	rendered := []io.Reader{solidPNG(t, blue, 1080, 1080), solidPNG(t, blue, 1080, 1080)}
+
+	got, err := ContactSheet(sources, rendered)
+	require.NoError(t, err)
+
+	img, err := decode(readAll(t, got))
+	require.NoError(t, err)
+
+	assert.Equal(t, image.Pt(2*sheetCell+3*sheetGap, 2*sheetCell+3*sheetGap), img.Bounds().Size())
+
+	center := func(row, col int) (int, int) {
+		return sheetGap + col*(sheetCell+sheetGap) + sheetCell/2, sheetGap + row*(sheetCell+sheetGap) + sheetCell/2
+	}
+
+	assertColor(t, red, img.At(center(0, 0)))
+	assertColor(t, blue, img.At(center(0, 1)))
+	assertColor(t, green, img.At(center(1, 0)))
+	assertColor(t, blue, img.At(center(1, 1)))
+
+	// Landscape source is fit into the cell, the rest of the cell is background.
+	x, y := center(1, 0)
+	assertColor(t, sheetBackground, img.At(x, y-sheetCell/2+10))
+}
+
+func TestContactSheet_FilesNum(t *testing.T) {
+	_, err := ContactSheet([]io.Reader{solidPNG(t, red, 10, 10)}, nil)
+	require.ErrorIs(t, err, ErrFilesNum)
+}
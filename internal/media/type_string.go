@@ -10,12 +10,16 @@ func _() {
 	var x [1]struct{}
 	_ = x[TypeUndefined-0]
 	_ = x[TypeStoryPhoto-1]
-	_ = x[typeSentinel-2]
+	_ = x[TypeFeedPhoto-2]
+	_ = x[TypeCarousel-3]
+	_ = x[TypeVideo-4]
+	_ = x[TypeStoryVideo-5]
+	_ = x[typeSentinel-6]
 }
 
-const _Type_name = "undefinedstory_photosentinel"
+const _Type_name = "undefinedstory_photofeed_photocarouselvideostory_videosentinel"
 
-var _Type_index = [...]uint8{0, 9, 20, 28}
+var _Type_index = [...]uint8{0, 9, 20, 30, 38, 43, 54, 62}
 
 func (i Type) String() string {
 	if i >= Type(len(_Type_index)-1) {
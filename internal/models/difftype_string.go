@@ -11,12 +11,13 @@ func _() {
 	_ = x[DiffTypeUnknown-0]
 	_ = x[DiffTypeFollowers-1]
 	_ = x[DiffTypeFollowings-2]
-	_ = x[diffTypeSentinel-3]
+	_ = x[DiffTypeBlocked-3]
+	_ = x[diffTypeSentinel-4]
 }
 
-const _DiffType_name = "UnknownFollowersFollowingsdiffTypeSentinel"
+const _DiffType_name = "UnknownFollowersFollowingsBlockeddiffTypeSentinel"
 
-var _DiffType_index = [...]uint8{0, 7, 16, 26, 42}
+var _DiffType_index = [...]uint8{0, 7, 16, 26, 33, 49}
 
 func (i DiffType) String() string {
 	if i >= DiffType(len(_DiffType_index)-1) {
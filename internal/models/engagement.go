@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// Post represents media post of the account.
+type Post struct {
+	ID       string    `bson:"id"`
+	Code     string    `bson:"code"`
+	TakenAt  time.Time `bson:"taken_at"`
+	Likes    int       `bson:"likes"`
+	Comments int       `bson:"comments"`
+	Caption  string    `bson:"caption"`
+	// MediaType is instagram media type, e.g. PHOTO, VIDEO or CAROUSEL, it is needed to delete the post.
+	MediaType string `bson:"media_type"`
+}
+
+// UserEngagement represents how many times user interacted with account posts.
+type UserEngagement struct {
+	User     User `bson:"user"`
+	Likes    int  `bson:"likes"`
+	Comments int  `bson:"comments"`
+}
+
+// Engagement represents users engagement over the window of the last posts.
+type Engagement struct {
+	Users     []UserEngagement `bson:"users"`
+	Posts     []Post           `bson:"posts"`
+	CreatedAt time.Time        `bson:"created_at"`
+}
+
+// MakeEngagement counts users engagement from likers and commenters of the posts.
+// Commenters contain user once per comment.
+func MakeEngagement(posts []Post, likers, commenters map[string][]User, created time.Time) Engagement {
+	byID := make(map[int64]*UserEngagement)
+
+	var order []int64
+
+	get := func(u User) *UserEngagement {
+		ue, ok := byID[u.ID]
+		if !ok {
+			ue = &UserEngagement{User: u}
+			byID[u.ID] = ue
+
+			order = append(order, u.ID)
+		}
+
+		return ue
+	}
+
+	for _, p := range posts {
+		for _, u := range likers[p.ID] {
+			get(u).Likes++
+		}
+
+		for _, u := range commenters[p.ID] {
+			get(u).Comments++
+		}
+	}
+
+	users := make([]UserEngagement, 0, len(order))
+
+	for _, id := range order {
+		users = append(users, *byID[id])
+	}
+
+	return Engagement{
+		Users:     users,
+		Posts:     posts,
+		CreatedAt: created,
+	}
+}
+
+// Interactions returns total number of likes and comments of the post.
+func (p Post) Interactions() int {
+	return p.Likes + p.Comments
+}
+
+// Interactions returns total number of likes and comments of the user.
+func (ue UserEngagement) Interactions() int {
+	return ue.Likes + ue.Comments
+}
+
+// PostsMetrics represents snapshot of the last posts metrics.
+type PostsMetrics struct {
+	Posts     []Post    `bson:"posts"`
+	Followers int       `bson:"followers"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// EngagementRate returns average number of likes and comments per post related to followers count, in percents.
+func (m PostsMetrics) EngagementRate() float64 {
+	if len(m.Posts) == 0 || m.Followers == 0 {
+		return 0
+	}
+
+	var total int
+
+	for _, p := range m.Posts {
+		total += p.Interactions()
+	}
+
+	const percents = 100
+
+	return float64(total) / float64(len(m.Posts)) / float64(m.Followers) * percents
+}
+
+// EngagementRate represents engagement rate at the moment of time.
+type EngagementRate struct {
+	Rate      float64
+	CreatedAt time.Time
+}
+
+// EngagementReport represents account posts engagement analytics.
+type EngagementReport struct {
+	// Rates is engagement rate history, oldest first.
+	Rates []EngagementRate
+	// TopPosts are posts with the most likes and comments.
+	TopPosts []Post
+	// TopFollowers are followers that engage the most.
+	TopFollowers []UserEngagement
+}
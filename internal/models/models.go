@@ -53,6 +53,26 @@ func MakeUsersBatch(bt UsersBatchType, users []User, created time.Time) UsersBat
 	UsersBatchTypeNewFollowings
 	// UsersBatchTypeLostFollowings represents lost followings.
 	UsersBatchTypeLostFollowings
+	// UsersBatchTypeApprovedRequests represents approved incoming follow requests.
+	UsersBatchTypeApprovedRequests
+	// UsersBatchTypeDeclinedRequests represents declined incoming follow requests.
+	UsersBatchTypeDeclinedRequests
+	// UsersBatchTypeOutgoingRequests represents sent follow requests that wait for approval.
+	UsersBatchTypeOutgoingRequests
+	// UsersBatchTypeCanceledRequests represents canceled outgoing follow requests.
+	UsersBatchTypeCanceledRequests
+	// UsersBatchTypeBlocked represents blocked users.
+	UsersBatchTypeBlocked
+	// UsersBatchTypeNewBlocked represents newly blocked users.
+	UsersBatchTypeNewBlocked
+	// UsersBatchTypeUnblocked represents users that are not blocked anymore.
+	UsersBatchTypeUnblocked
+	// UsersBatchTypeCloseFriends represents close friends list.
+	UsersBatchTypeCloseFriends
+	// UsersBatchTypeGhostFollowers represents followers that never engaged with the last posts.
+	UsersBatchTypeGhostFollowers
+	// UsersBatchTypeWhitelisted represents users whitelisted in addition to the config whitelist.
+	UsersBatchTypeWhitelisted
 
 	usersBatchTypeSentinel // should be always last. New types should be added at the end before sentinel.
 )
@@ -67,6 +87,34 @@ func MakeUser(id int64, username, fullname string) User {
 	return User{ID: id, UserName: username, FullName: fullname}
 }
 
+//go:generate stringer -type=FollowStatus -trimprefix=FollowStatus -linecomment
+
+// FollowStatus represents result of the follow action.
+type FollowStatus uint
+
+const (
+	// FollowStatusUnknown is unknown status, to cover default value case.
+	FollowStatusUnknown FollowStatus = iota // unknown
+
+	// FollowStatusFollowing means that user is followed.
+	FollowStatusFollowing // following
+	// FollowStatusRequested means that user has private account and follow request waits for approval.
+	FollowStatusRequested // requested
+
+	followStatusSentinel // sentinel
+)
+
+// Valid checks if value is valid status.
+func (i FollowStatus) Valid() bool {
+	return i > FollowStatusUnknown && i < followStatusSentinel
+}
+
+// OutgoingRequest represents sent follow request that waits for approval.
+type OutgoingRequest struct {
+	User        User
+	RequestedAt time.Time
+}
+
 // Limits represents action limits.
 type Limits struct {
 	Follow   int
@@ -86,6 +134,8 @@ type Limits struct {
 	DiffTypeFollowers
 	// DiffTypeFollowings represents followings history.
 	DiffTypeFollowings
+	// DiffTypeBlocked represents blocked users history.
+	DiffTypeBlocked
 
 	diffTypeSentinel // should be always last. New types should be added at the end before sentinel.
 )
@@ -131,3 +181,12 @@ func MakeDiffHistory(dt DiffType) DiffHistory {
 		History:  make(map[time.Time][]UsersBatch),
 	}
 }
+
+// SessionInfo represents details of the stored client session.
+type SessionInfo struct {
+	Username  string
+	FilePath  string
+	UpdatedAt time.Time
+	Encrypted bool
+	Valid     bool
+}
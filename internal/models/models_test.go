@@ -2,6 +2,7 @@
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -74,3 +75,72 @@ type args struct {
 		})
 	}
 }
+
+func TestMakeEngagement(t *testing.T) {
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+
+	posts := []models.Post{{ID: "10_1"}, {ID: "11_1"}}
+
+	created := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	got := models.MakeEngagement(posts,
+		map[string][]models.User{
+			"10_1": {alice, bob},
+			"11_1": {alice},
+			// not in the window.
+			"12_1": {bob},
+		},
+		map[string][]models.User{
+			"11_1": {bob, bob},
+		},
+		created,
+	)
+
+	assert.Equal(t, models.Engagement{
+		Users: []models.UserEngagement{
+			{User: alice, Likes: 2, Comments: 0},
+			{User: bob, Likes: 1, Comments: 2},
+		},
+		Posts:     posts,
+		CreatedAt: created,
+	}, got)
+}
+
+func TestPostsMetrics_EngagementRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics models.PostsMetrics
+		want    float64
+	}{
+		{
+			name: "rate",
+			metrics: models.PostsMetrics{
+				Posts:     []models.Post{{Likes: 8, Comments: 2}, {Likes: 4, Comments: 6}},
+				Followers: 200,
+			},
+			want: 5,
+		},
+		{
+			name: "no followers",
+			metrics: models.PostsMetrics{
+				Posts: []models.Post{{Likes: 8}},
+			},
+			want: 0,
+		},
+		{
+			name: "no posts",
+			metrics: models.PostsMetrics{
+				Followers: 200,
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.metrics.EngagementRate(), 0.0001)
+		})
+	}
+}
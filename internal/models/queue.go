@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/media"
+)
+
+//go:generate stringer -type=QueueStatus -trimprefix=QueueStatus -linecomment
+
+// QueueStatus represents state of the scheduled post.
+type QueueStatus uint
+
+const (
+	// QueueStatusUnknown is unknown status, to cover default value case.
+	QueueStatusUnknown QueueStatus = iota // unknown
+
+	// QueueStatusPending means that post waits for its time or the next retry.
+	QueueStatusPending // pending
+	// QueueStatusDone means that post is uploaded.
+	QueueStatusDone // done
+	// QueueStatusFailed means that all upload attempts failed.
+	QueueStatusFailed // failed
+
+	queueStatusSentinel // sentinel
+)
+
+// Valid checks if value is valid status.
+func (i QueueStatus) Valid() bool {
+	return i > QueueStatusUnknown && i < queueStatusSentinel
+}
+
+// QueueItem represents scheduled post.
+type QueueItem struct {
+	ID string `bson:"id"`
+	// Files are absolute paths to the media files, read at upload time.
+	Files       []string          `bson:"files"`
+	Type        media.Type        `bson:"type"`
+	Post        media.PostOptions `bson:"post"`
+	ScheduledAt time.Time         `bson:"scheduled_at"`
+	Status      QueueStatus       `bson:"status"`
+	// Attempts is a number of failed upload attempts.
+	Attempts int `bson:"attempts"`
+	// NextAttemptAt is time of the next retry after failed attempt.
+	NextAttemptAt time.Time `bson:"next_attempt_at"`
+	// LastError is an error of the last failed attempt.
+	LastError  string    `bson:"last_error"`
+	UploadedAt time.Time `bson:"uploaded_at"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// Due checks if pending post should be uploaded at the moment.
+func (q QueueItem) Due(now time.Time) bool {
+	return q.Status == QueueStatusPending && !now.Before(q.ScheduledAt) && !now.Before(q.NextAttemptAt)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Story represents story of the account.
+type Story struct {
+	ID        string    `bson:"id"`
+	TakenAt   time.Time `bson:"taken_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	// Views is total number of views reported by instagram.
+	Views int `bson:"views"`
+	// MediaType is instagram media type, PHOTO or VIDEO, it is needed to delete the story.
+	MediaType string `bson:"media_type"`
+}
+
+// StoryViewers represents viewers of the story at the moment of time.
+type StoryViewers struct {
+	Story     Story     `bson:"story"`
+	Users     []User    `bson:"users"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// UserViews represents how many stories user viewed.
+type UserViews struct {
+	User  User
+	Views int
+}
+
+// StoryViewsReport represents followers ranking by stories views.
+type StoryViewsReport struct {
+	// Stories is number of stories with stored viewers.
+	Stories int
+	// Viewers are followers that viewed stories, the most active first.
+	Viewers []UserViews
+	// NeverViewed are followers that viewed none of the stories.
+	NeverViewed []User
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// Upload represents successfully uploaded media file.
+type Upload struct {
+	// Hash is a hex encoded SHA-256 of the source file content.
+	Hash string `bson:"hash"`
+	// File is a source file name.
+	File string `bson:"file"`
+	// Type is a media type the file was uploaded as.
+	Type string `bson:"type"`
+	// MediaID is an id of the created instagram media, files of the carousel share it.
+	MediaID   string    `bson:"media_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// OwnMedia represents media posted by the account.
+type OwnMedia struct {
+	// Posts are feed posts, newest first.
+	Posts []Post
+	// Stories are active stories, oldest first.
+	Stories []Story
+}
+
+// UploadDirReport represents outcome of the directory upload.
+type UploadDirReport struct {
+	// Uploaded are files uploaded during the run.
+	Uploaded []string
+	// Skipped are files with content uploaded before.
+	Skipped []string
+	// Failed are files that could not be read, processed or uploaded.
+	Failed []string
+}
@@ -17,12 +17,22 @@ func _() {
 	_ = x[UsersBatchTypeNewFollowers-6]
 	_ = x[UsersBatchTypeNewFollowings-7]
 	_ = x[UsersBatchTypeLostFollowings-8]
-	_ = x[usersBatchTypeSentinel-9]
+	_ = x[UsersBatchTypeApprovedRequests-9]
+	_ = x[UsersBatchTypeDeclinedRequests-10]
+	_ = x[UsersBatchTypeOutgoingRequests-11]
+	_ = x[UsersBatchTypeCanceledRequests-12]
+	_ = x[UsersBatchTypeBlocked-13]
+	_ = x[UsersBatchTypeNewBlocked-14]
+	_ = x[UsersBatchTypeUnblocked-15]
+	_ = x[UsersBatchTypeCloseFriends-16]
+	_ = x[UsersBatchTypeGhostFollowers-17]
+	_ = x[UsersBatchTypeWhitelisted-18]
+	_ = x[usersBatchTypeSentinel-19]
 }
 
-const _UsersBatchType_name = "UnknownFollowersFollowingsNotMutualUselessFollowersLostFollowersNewFollowersNewFollowingsLostFollowingsusersBatchTypeSentinel"
+const _UsersBatchType_name = "UnknownFollowersFollowingsNotMutualUselessFollowersLostFollowersNewFollowersNewFollowingsLostFollowingsApprovedRequestsDeclinedRequestsOutgoingRequestsCanceledRequestsBlockedNewBlockedUnblockedCloseFriendsGhostFollowersWhitelistedusersBatchTypeSentinel"
 
-var _UsersBatchType_index = [...]uint8{0, 7, 16, 26, 35, 51, 64, 76, 89, 103, 125}
+var _UsersBatchType_index = [...]uint8{0, 7, 16, 26, 35, 51, 64, 76, 89, 103, 119, 135, 151, 167, 174, 184, 193, 205, 219, 230, 252}
 
 func (i UsersBatchType) String() string {
 	if i < 0 || i >= UsersBatchType(len(_UsersBatchType_index)-1) {
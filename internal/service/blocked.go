@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/actions"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+// GetBlocked returns list of users blocked by logged-in user.
+// Each call stores snapshot of blocked list, so it could be compared over time.
+func (svc *Service) GetBlocked(ctx context.Context) ([]models.User, error) {
+	stop := spinner.Set("Fetching blocked users", "", "yellow")
+	defer stop()
+
+	return svc.getUsers(ctx, models.UsersBatchTypeBlocked)
+}
+
+// BlockUsers blocks users by the name passed.
+func (svc *Service) BlockUsers(ctx context.Context, usernames []string) (int, error) {
+	var f userListProcessFunc = func(ctx context.Context, uslist []models.User) (int, error) {
+		return svc.actUsers(ctx, uslist, actions.UserActionBlock, false)
+	}
+
+	return svc.processByUsernames(ctx, usernames, f)
+}
+
+// UnblockUsers unblocks users by the name passed.
+// Blocked users profiles are not available by name, so users are looked up in the blocked list.
+func (svc *Service) UnblockUsers(ctx context.Context, usernames []string) (int, error) {
+	if len(usernames) == 0 {
+		return 0, ErrNoUsernamesPassed
+	}
+
+	blocked, err := svc.GetBlocked(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get blocked users: %w", err)
+	}
+
+	users, notFound := filterByUsernames(blocked, usernames)
+	if len(notFound) != 0 {
+		log.WithError(ctx, fmt.Errorf("[ %s ]: %w", strings.Join(notFound, ","), ErrUserNotBlocked)).
+			Warn("Some users are not blocked")
+	}
+
+	if len(users) == 0 {
+		return 0, makeNoUsersError(models.UsersBatchTypeBlocked)
+	}
+
+	return svc.actUsers(ctx, users, actions.UserActionUnblock, false)
+}
+
+// GetDiffBlocked returns batches with newly blocked and unblocked users.
+func (svc *Service) GetDiffBlocked(ctx context.Context) ([]models.UsersBatch, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return svc.getDiff(ctx, models.DiffTypeBlocked)
+}
+
+// GetHistoryDiffBlocked returns diff history of blocked users for an account.
+func (svc *Service) GetHistoryDiffBlocked(ctx context.Context) (models.DiffHistory, error) {
+	if ctx.Err() != nil {
+		return models.DiffHistory{}, ctx.Err()
+	}
+
+	return svc.getHistoryDiff(ctx, models.DiffTypeBlocked)
+}
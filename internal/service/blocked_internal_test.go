@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_GetDiffBlocked(t *testing.T) {
+	ctx := context.Background()
+
+	spammer := models.MakeUser(70, "spammer", "Spam Bot")
+	troll := models.MakeUser(71, "troll", "")
+	stalker := models.MakeUser(72, "stalker", "")
+
+	cl := &fakeClient{
+		blocked: []models.User{spammer, troll},
+	}
+
+	svc := newTestService(t, cl)
+
+	_, err := svc.GetBlocked(ctx)
+	require.NoError(t, err)
+
+	cl.blocked = []models.User{spammer, stalker}
+
+	got, err := svc.GetBlocked(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cl.blocked, got)
+
+	diff, err := svc.GetDiffBlocked(ctx)
+	require.NoError(t, err)
+	require.Len(t, diff, 2)
+
+	assert.Equal(t, models.UsersBatchTypeNewBlocked, diff[0].Type)
+	assert.Equal(t, []models.User{stalker}, diff[0].Users)
+	assert.Equal(t, models.UsersBatchTypeUnblocked, diff[1].Type)
+	assert.Equal(t, []models.User{troll}, diff[1].Users)
+}
+
+func TestService_UnblockUsers(t *testing.T) {
+	ctx := context.Background()
+
+	spammer := models.MakeUser(70, "spammer", "Spam Bot")
+	troll := models.MakeUser(71, "troll", "")
+
+	cl := &fakeClient{
+		blocked: []models.User{spammer, troll},
+	}
+
+	svc := newTestService(t, cl)
+
+	count, err := svc.UnblockUsers(ctx, []string{"Troll", "nobody"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []models.User{troll}, cl.unblocked)
+
+	_, err = svc.UnblockUsers(ctx, []string{"nobody"})
+	require.ErrorIs(t, err, ErrNoUsers)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+// GetCloseFriends returns close friends list and stores its snapshot.
+func (svc *Service) GetCloseFriends(ctx context.Context) ([]models.User, error) {
+	stop := spinner.Set("Fetching close friends", "", "yellow")
+	defer stop()
+
+	users, err := svc.instagram.client.CloseFriends(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get close friends: %w", err)
+	}
+
+	svc.storeCloseFriends(ctx, users)
+
+	return users, nil
+}
+
+// AddCloseFriends adds users by the name passed to close friends list.
+func (svc *Service) AddCloseFriends(ctx context.Context, usernames []string) (int, error) {
+	var f userListProcessFunc = func(ctx context.Context, uslist []models.User) (int, error) {
+		if len(uslist) == 0 {
+			return 0, makeNoUsersError(models.UsersBatchTypeCloseFriends)
+		}
+
+		if err := svc.instagram.client.SetCloseFriends(ctx, uslist, nil); err != nil {
+			return 0, fmt.Errorf("add close friends: %w", err)
+		}
+
+		return len(uslist), nil
+	}
+
+	return svc.processByUsernames(ctx, usernames, f)
+}
+
+// RemoveCloseFriends removes users by the name passed from close friends list.
+func (svc *Service) RemoveCloseFriends(ctx context.Context, usernames []string) (int, error) {
+	if len(usernames) == 0 {
+		return 0, ErrNoUsernamesPassed
+	}
+
+	current, err := svc.GetCloseFriends(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	users, notFound := filterByUsernames(current, usernames)
+	if len(notFound) != 0 {
+		log.WithError(ctx, fmt.Errorf("[ %s ]: %w", strings.Join(notFound, ","), ErrNotCloseFriend)).
+			Warn("Some users are not in close friends list")
+	}
+
+	if len(users) == 0 {
+		return 0, makeNoUsersError(models.UsersBatchTypeCloseFriends)
+	}
+
+	if err = svc.instagram.client.SetCloseFriends(ctx, nil, users); err != nil {
+		return 0, fmt.Errorf("remove close friends: %w", err)
+	}
+
+	return len(users), nil
+}
+
+// SyncCloseFriends sets close friends list to the passed users. Returns number of added and removed users.
+// Empty list is refused, so close friends are never cleared by mistake.
+func (svc *Service) SyncCloseFriends(ctx context.Context, target []models.User) (int, int, error) {
+	if len(target) == 0 {
+		return 0, 0, makeNoUsersError(models.UsersBatchTypeCloseFriends)
+	}
+
+	current, err := svc.GetCloseFriends(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	add, remove := getNew(current, target), getLost(current, target)
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, 0, nil
+	}
+
+	if err = svc.instagram.client.SetCloseFriends(ctx, add, remove); err != nil {
+		return 0, 0, fmt.Errorf("sync close friends: %w", err)
+	}
+
+	svc.storeCloseFriends(ctx, target)
+
+	return len(add), len(remove), nil
+}
+
+// GetMutualsOlderThan returns mutual followers that follow and are followed by the account
+// longer than passed age according to stored history.
+func (svc *Service) GetMutualsOlderThan(ctx context.Context, age time.Duration) ([]models.User, error) {
+	// fetching stores current snapshots, so history is up-to-date.
+	followers, err := svc.GetFollowers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get followers: %w", err)
+	}
+
+	followings, err := svc.GetFollowings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get followings: %w", err)
+	}
+
+	followersHistory, err := svc.getAllBatches(ctx, models.UsersBatchTypeFollowers)
+	if err != nil {
+		return nil, err
+	}
+
+	followingsHistory, err := svc.getAllBatches(ctx, models.UsersBatchTypeFollowings)
+	if err != nil {
+		return nil, err
+	}
+
+	return mutualsSince(
+		followers,
+		followings,
+		relationSince(followersHistory),
+		relationSince(followingsHistory),
+		time.Now().Add(-age),
+	), nil
+}
+
+func (svc *Service) storeCloseFriends(ctx context.Context, users []models.User) {
+	batch := models.MakeUsersBatch(models.UsersBatchTypeCloseFriends, users, time.Now())
+
+	if err := svc.storeUsers(ctx, batch); err != nil && !errors.Is(err, ErrNoUsers) {
+		log.WithError(ctx, err).Warn("Failed to store close friends snapshot")
+	}
+}
+
+// relationSince returns for each user of the last snapshot the time since which user is continuously present in snapshots.
+func relationSince(batches []models.UsersBatch) map[int64]time.Time {
+	sorted := make([]models.UsersBatch, len(batches))
+	copy(sorted, batches)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	since := make(map[int64]time.Time)
+
+	for _, b := range sorted {
+		present := make(map[int64]bool, len(b.Users))
+
+		for _, u := range b.Users {
+			present[u.ID] = true
+
+			if _, ok := since[u.ID]; !ok {
+				since[u.ID] = b.CreatedAt
+			}
+		}
+
+		for id := range since {
+			if !present[id] {
+				delete(since, id)
+			}
+		}
+	}
+
+	return since
+}
+
+// mutualsSince returns followers that are followed back and both relations started before passed time.
+func mutualsSince(
+	followers, followings []models.User,
+	followerSince, followingSince map[int64]time.Time,
+	before time.Time,
+) []models.User {
+	followed := make(map[int64]bool, len(followings))
+
+	for _, u := range followings {
+		followed[u.ID] = true
+	}
+
+	result := make([]models.User, 0, len(followers))
+
+	for _, u := range followers {
+		if !followed[u.ID] {
+			continue
+		}
+
+		fs, ok := followerSince[u.ID]
+		if !ok || fs.After(before) {
+			continue
+		}
+
+		fgs, ok := followingSince[u.ID]
+		if !ok || fgs.After(before) {
+			continue
+		}
+
+		result = append(result, u)
+	}
+
+	return result
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func Test_relationSince(t *testing.T) {
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	bt := models.UsersBatchTypeFollowers
+
+	got := relationSince([]models.UsersBatch{
+		models.MakeUsersBatch(bt, []models.User{alice, bob, carol}, day3),
+		models.MakeUsersBatch(bt, []models.User{alice, bob}, day1),
+		// carol unfollowed and returned, bob is gone.
+		models.MakeUsersBatch(bt, []models.User{alice}, day2),
+	})
+
+	assert.Equal(t, map[int64]time.Time{
+		alice.ID: day1,
+		bob.ID:   day3,
+		carol.ID: day3,
+	}, got)
+}
+
+func Test_mutualsSince(t *testing.T) {
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+	dave := models.MakeUser(4, "dave", "Dave")
+
+	old := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fresh := old.AddDate(0, 6, 0)
+	before := old.AddDate(0, 3, 0)
+
+	got := mutualsSince(
+		[]models.User{alice, bob, carol, dave},
+		[]models.User{alice, bob, carol},
+		map[int64]time.Time{alice.ID: old, bob.ID: fresh, carol.ID: old, dave.ID: old},
+		map[int64]time.Time{alice.ID: old, bob.ID: old, carol.ID: fresh},
+		before,
+	)
+
+	assert.Equal(t, []models.User{alice}, got)
+}
+
+func TestService_SyncCloseFriends(t *testing.T) {
+	ctx := context.Background()
+
+	heidi := models.MakeUser(80, "heidi", "Heidi")
+	ivan := models.MakeUser(81, "ivan", "Ivan")
+	judy := models.MakeUser(82, "judy", "")
+
+	cl := &fakeClient{
+		closeFriends: []models.User{heidi, ivan},
+	}
+
+	svc := newTestService(t, cl)
+
+	added, removed, err := svc.SyncCloseFriends(ctx, []models.User{ivan, judy})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []models.User{ivan, judy}, cl.closeFriends)
+
+	added, removed, err = svc.SyncCloseFriends(ctx, []models.User{judy, ivan})
+	require.NoError(t, err)
+	assert.Zero(t, added)
+	assert.Zero(t, removed)
+
+	_, _, err = svc.SyncCloseFriends(ctx, nil)
+	require.ErrorIs(t, err, ErrNoUsers)
+
+	count, err := svc.RemoveCloseFriends(ctx, []string{"IVAN", "nobody"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []models.User{judy}, cl.closeFriends)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+// GetEngagement fetches likers and commenters of the last posts and stores users engagement.
+func (svc *Service) GetEngagement(ctx context.Context, posts int) (models.Engagement, error) {
+	stop := spinner.Set("Fetching posts engagement", "", "yellow")
+	defer stop()
+
+	if posts <= 0 {
+		return models.Engagement{}, ErrNoPosts
+	}
+
+	cl := svc.instagram.client
+
+	list, err := cl.Posts(ctx, posts)
+	if err != nil {
+		return models.Engagement{}, fmt.Errorf("get posts: %w", err)
+	}
+
+	if len(list) == 0 {
+		return models.Engagement{}, ErrNoPosts
+	}
+
+	likers := make(map[string][]models.User, len(list))
+	commenters := make(map[string][]models.User, len(list))
+
+	for _, p := range list {
+		if likers[p.ID], err = cl.Likers(ctx, p); err != nil {
+			return models.Engagement{}, fmt.Errorf("get likers [%s]: %w", p.Code, err)
+		}
+
+		if commenters[p.ID], err = cl.Commenters(ctx, p); err != nil {
+			return models.Engagement{}, fmt.Errorf("get commenters [%s]: %w", p.Code, err)
+		}
+	}
+
+	engagement := models.MakeEngagement(list, likers, commenters, time.Now())
+
+	if err = svc.storage.InsertEngagement(ctx, engagement); err != nil {
+		return models.Engagement{}, fmt.Errorf("store engagement: %w", err)
+	}
+
+	return engagement, nil
+}
+
+// GetEngagementReport fetches the last posts engagement, stores posts metrics snapshot
+// and returns engagement rate history with top posts and the most engaged followers.
+func (svc *Service) GetEngagementReport(ctx context.Context, posts, top int) (models.EngagementReport, error) {
+	engagement, err := svc.GetEngagement(ctx, posts)
+	if err != nil {
+		return models.EngagementReport{}, err
+	}
+
+	followers, err := svc.GetFollowers(ctx)
+	if err != nil {
+		return models.EngagementReport{}, fmt.Errorf("get followers: %w", err)
+	}
+
+	metrics := models.PostsMetrics{
+		Posts:     engagement.Posts,
+		Followers: len(followers),
+		CreatedAt: time.Now(),
+	}
+
+	if err = svc.storage.InsertPostsMetrics(ctx, metrics); err != nil {
+		return models.EngagementReport{}, fmt.Errorf("store posts metrics: %w", err)
+	}
+
+	history, err := svc.storage.GetAllPostsMetrics(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoData) {
+		return models.EngagementReport{}, fmt.Errorf("get posts metrics: %w", err)
+	}
+
+	return models.EngagementReport{
+		Rates:        engagementRates(history),
+		TopPosts:     topPosts(engagement.Posts, top),
+		TopFollowers: topFollowers(engagement.Users, followers, top),
+	}, nil
+}
+
+func engagementRates(history []models.PostsMetrics) []models.EngagementRate {
+	rates := make([]models.EngagementRate, 0, len(history))
+
+	for _, m := range history {
+		rates = append(rates, models.EngagementRate{
+			Rate:      m.EngagementRate(),
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	return rates
+}
+
+// topPosts returns up to n posts with the most interactions, newer post goes first on equal interactions.
+func topPosts(posts []models.Post, n int) []models.Post {
+	sorted := make([]models.Post, len(posts))
+	copy(sorted, posts)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Interactions() == sorted[j].Interactions() {
+			return sorted[i].TakenAt.After(sorted[j].TakenAt)
+		}
+
+		return sorted[i].Interactions() > sorted[j].Interactions()
+	})
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	return sorted
+}
+
+// topFollowers returns up to n followers with the most interactions. Users that don't follow the account are skipped.
+func topFollowers(users []models.UserEngagement, followers []models.User, n int) []models.UserEngagement {
+	isFollower := make(map[int64]bool, len(followers))
+
+	for _, u := range followers {
+		isFollower[u.ID] = true
+	}
+
+	result := make([]models.UserEngagement, 0, len(users))
+
+	for _, ue := range users {
+		if isFollower[ue.User.ID] {
+			result = append(result, ue)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Interactions() > result[j].Interactions()
+	})
+
+	if len(result) > n {
+		result = result[:n]
+	}
+
+	return result
+}
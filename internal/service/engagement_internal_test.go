@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func Test_topPosts(t *testing.T) {
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	p1 := models.Post{ID: "p1", TakenAt: day1, Likes: 3, Comments: 1}
+	p2 := models.Post{ID: "p2", TakenAt: day2, Likes: 1, Comments: 3}
+	p3 := models.Post{ID: "p3", TakenAt: day2, Likes: 10}
+	p4 := models.Post{ID: "p4", TakenAt: day2}
+
+	posts := []models.Post{p1, p2, p3, p4}
+
+	assert.Equal(t, []models.Post{p3, p2, p1}, topPosts(posts, 3))
+	assert.Equal(t, []models.Post{p1, p2, p3, p4}, posts, "input is not changed")
+}
+
+func TestService_GetEngagementReport(t *testing.T) {
+	ctx := context.Background()
+
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+	// not a follower.
+	eve := models.MakeUser(5, "eve", "Eve")
+
+	p1 := models.Post{ID: "p1", Code: "C1", Likes: 2, Comments: 3}
+	p2 := models.Post{ID: "p2", Code: "C2", Likes: 3, Comments: 0}
+
+	cl := &fakeClient{
+		followers: []models.User{alice, bob, carol, models.MakeUser(4, "dave", "")},
+		posts:     []models.Post{p1, p2},
+		likers: map[string][]models.User{
+			"p1": {alice, eve},
+			"p2": {alice, bob, eve},
+		},
+		commenters: map[string][]models.User{
+			"p1": {eve, eve, carol},
+		},
+	}
+
+	svc := newTestService(t, cl)
+
+	_, err := svc.GetEngagementReport(ctx, 10, 2)
+	require.NoError(t, err)
+
+	cl.followers = append(cl.followers, eve)
+
+	got, err := svc.GetEngagementReport(ctx, 10, 2)
+	require.NoError(t, err)
+
+	require.Len(t, got.Rates, 2)
+	// 8 interactions over 2 posts, 4 and then 5 followers.
+	assert.InDelta(t, 100.0, got.Rates[0].Rate, 0.0001)
+	assert.InDelta(t, 80.0, got.Rates[1].Rate, 0.0001)
+
+	assert.Equal(t, []models.Post{p1, p2}, got.TopPosts)
+	assert.Equal(t, []models.UserEngagement{
+		{User: eve, Likes: 2, Comments: 2},
+		{User: alice, Likes: 2, Comments: 0},
+	}, got.TopFollowers)
+}
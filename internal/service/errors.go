@@ -13,6 +13,8 @@
 	ErrLimitExceed = errors.New("limit exceeded")
 	// ErrCorrupted returned when instagram returned error response more than one time during loop processing.
 	ErrCorrupted = errors.New("unable to continue - instagram responses with errors")
+	// ErrRateLimited returned when instagram keeps rate limiting requests after retries.
+	ErrRateLimited = errors.New("instagram rate limit reached")
 	// ErrNoUsers means that no users found.
 	ErrNoUsers = errors.New("no users")
 	// ErrNoUsernamesPassed returns when usernames list is empty.
@@ -21,6 +23,26 @@
 	ErrUserInWhitelist = errors.New("user in whitelist")
 	// ErrUserNotFound returned when user not found.
 	ErrUserNotFound = errors.New("user not found")
+	// ErrNoPendingRequest returned when user has no pending follow request.
+	ErrNoPendingRequest = errors.New("no pending follow request")
+	// ErrUserNotBlocked returned when user is not in the blocked list.
+	ErrUserNotBlocked = errors.New("user is not blocked")
+	// ErrNotCloseFriend returned when user is not in the close friends list.
+	ErrNotCloseFriend = errors.New("user is not in close friends")
+	// ErrNoPosts returned when account has no posts to analyze.
+	ErrNoPosts = errors.New("no posts")
+	// ErrQueueItemNotFound returned when there is no scheduled post with passed id.
+	ErrQueueItemNotFound = errors.New("scheduled post not found")
+	// ErrQueueLocalStorage returned when scheduled posts are used with local storage that is lost on exit.
+	ErrQueueLocalStorage = errors.New("scheduled posts need mongo storage, local storage is lost on exit")
+	// ErrMediaNotFound returned when there is no feed post or active story with passed id.
+	ErrMediaNotFound = errors.New("media not found")
+	// ErrEmptyMediaID returned when media id is not passed.
+	ErrEmptyMediaID = errors.New("media id is empty")
+	// ErrUploadHistory returned when media is uploaded, but not stored in the upload history.
+	ErrUploadHistory = errors.New("media is uploaded, but not stored in upload history")
+	// ErrRenderVideo returned when video render is requested, videos are uploaded as is.
+	ErrRenderVideo = errors.New("videos are uploaded as is, nothing to render")
 )
 
 func makeNoUsersError(t models.UsersBatchType) error {
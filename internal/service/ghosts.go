@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// GetGhostFollowers returns followers that neither liked nor commented any of the last posts.
+// Engagement of users over the posts window is stored.
+func (svc *Service) GetGhostFollowers(ctx context.Context, posts int) ([]models.User, error) {
+	engagement, err := svc.GetEngagement(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := svc.GetFollowers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get followers: %w", err)
+	}
+
+	ghosts := ghostFollowers(followers, engagement)
+
+	bt := models.UsersBatchTypeGhostFollowers
+
+	if err = svc.storeUsers(ctx, models.MakeUsersBatch(bt, ghosts, time.Now())); err != nil && !errors.Is(err, ErrNoUsers) {
+		return nil, fmt.Errorf("store users [%s]: %w", bt.String(), err)
+	}
+
+	return ghosts, nil
+}
+
+// ghostFollowers returns followers that have no engagement.
+func ghostFollowers(followers []models.User, e models.Engagement) []models.User {
+	engaged := make(map[int64]bool, len(e.Users))
+
+	for _, ue := range e.Users {
+		if ue.Likes > 0 || ue.Comments > 0 {
+			engaged[ue.User.ID] = true
+		}
+	}
+
+	ghosts := make([]models.User, 0, len(followers))
+
+	for _, u := range followers {
+		if !engaged[u.ID] {
+			ghosts = append(ghosts, u)
+		}
+	}
+
+	return ghosts
+}
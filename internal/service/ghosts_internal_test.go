@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_GetGhostFollowers(t *testing.T) {
+	ctx := context.Background()
+
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+	dave := models.MakeUser(4, "dave", "Dave")
+
+	cl := &fakeClient{
+		followers: []models.User{alice, bob, carol, dave},
+		posts: []models.Post{
+			{ID: "p2", Code: "C2"},
+			{ID: "p1", Code: "C1"},
+			{ID: "p0", Code: "C0"},
+		},
+		likers: map[string][]models.User{
+			"p2": {alice},
+			// out of the window.
+			"p0": {carol},
+		},
+		commenters: map[string][]models.User{
+			"p1": {bob, bob},
+		},
+	}
+
+	svc := newTestService(t, cl)
+
+	got, err := svc.GetGhostFollowers(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{carol, dave}, got)
+
+	e, err := svc.storage.GetLastEngagement(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []models.UserEngagement{
+		{User: alice, Likes: 1, Comments: 0},
+		{User: bob, Likes: 0, Comments: 2},
+	}, e.Users)
+
+	batch, err := svc.storage.GetLastUsersBatchByType(ctx, models.UsersBatchTypeGhostFollowers)
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{carol, dave}, batch.Users)
+}
+
+func TestService_GetGhostFollowers_NoPosts(t *testing.T) {
+	svc := newTestService(t, &fakeClient{})
+
+	_, err := svc.GetGhostFollowers(context.Background(), 10)
+	require.ErrorIs(t, err, ErrNoPosts)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/client"
+	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// fakeClient implements client.Client for service tests. Not overridden methods panic.
+type fakeClient struct {
+	client.Client
+
+	username string
+
+	pending []models.User
+	useless map[string]bool
+
+	followers  []models.User
+	followings []models.User
+	blocked    []models.User
+	// closeFriends is updated by SetCloseFriends calls.
+	closeFriends []models.User
+	// posts engagement, likers and commenters are keyed by post id.
+	posts      []models.Post
+	likers     map[string][]models.User
+	commenters map[string][]models.User
+	// stories viewers are keyed by story id.
+	stories      []models.Story
+	storyViewers map[string][]models.User
+	// private users get follow request instead of follow.
+	private map[string]bool
+
+	approved   []models.User
+	declined   []models.User
+	unfollowed []models.User
+	unblocked  []models.User
+	// uploaded are captions of uploaded media, uploadErr fails uploads when set.
+	uploaded  []string
+	uploadErr error
+	// deleted and archived are media ids, captions are keyed by media id.
+	deleted  []string
+	archived []string
+	captions map[string]string
+}
+
+func (f *fakeClient) Username(_ context.Context) string {
+	return f.username
+}
+
+func (f *fakeClient) BlockedUsers(_ context.Context) ([]models.User, error) {
+	return f.blocked, nil
+}
+
+func (f *fakeClient) CloseFriends(_ context.Context) ([]models.User, error) {
+	return f.closeFriends, nil
+}
+
+func (f *fakeClient) SetCloseFriends(_ context.Context, add, remove []models.User) error {
+	f.closeFriends = append(getLost(f.closeFriends, remove), add...)
+
+	return nil
+}
+
+func (f *fakeClient) Unblock(_ context.Context, user models.User) error {
+	f.unblocked = append(f.unblocked, user)
+
+	return nil
+}
+
+func (f *fakeClient) UploadMedia(_ context.Context, _ []io.Reader, _ media.Type, post media.PostOptions) (string, error) {
+	if f.uploadErr != nil {
+		return "", f.uploadErr
+	}
+
+	f.uploaded = append(f.uploaded, post.Caption)
+
+	return fmt.Sprintf("media_%d", len(f.uploaded)), nil
+}
+
+func (f *fakeClient) DeletePost(_ context.Context, post models.Post) error {
+	f.deleted = append(f.deleted, post.ID)
+
+	return nil
+}
+
+func (f *fakeClient) DeleteStory(_ context.Context, story models.Story) error {
+	f.deleted = append(f.deleted, story.ID)
+
+	return nil
+}
+
+func (f *fakeClient) ArchiveMedia(_ context.Context, id string) error {
+	f.archived = append(f.archived, id)
+
+	return nil
+}
+
+func (f *fakeClient) EditCaption(_ context.Context, id, caption string) error {
+	if f.captions == nil {
+		f.captions = make(map[string]string)
+	}
+
+	f.captions[id] = caption
+
+	return nil
+}
+
+func (f *fakeClient) Followers(_ context.Context) ([]models.User, error) {
+	return f.followers, nil
+}
+
+func (f *fakeClient) Posts(_ context.Context, limit int) ([]models.Post, error) {
+	if len(f.posts) > limit {
+		return f.posts[:limit], nil
+	}
+
+	return f.posts, nil
+}
+
+func (f *fakeClient) Likers(_ context.Context, post models.Post) ([]models.User, error) {
+	return f.likers[post.ID], nil
+}
+
+func (f *fakeClient) Commenters(_ context.Context, post models.Post) ([]models.User, error) {
+	return f.commenters[post.ID], nil
+}
+
+func (f *fakeClient) Stories(_ context.Context) ([]models.Story, error) {
+	return f.stories, nil
+}
+
+func (f *fakeClient) StoryViewers(_ context.Context, story models.Story) ([]models.User, error) {
+	return f.storyViewers[story.ID], nil
+}
+
+func (f *fakeClient) Followings(_ context.Context) ([]models.User, error) {
+	return f.followings, nil
+}
+
+func (f *fakeClient) Follow(_ context.Context, user models.User) (models.FollowStatus, error) {
+	if f.private[user.UserName] {
+		return models.FollowStatusRequested, nil
+	}
+
+	f.followings = append(f.followings, user)
+
+	return models.FollowStatusFollowing, nil
+}
+
+func (f *fakeClient) Unfollow(_ context.Context, user models.User) error {
+	f.unfollowed = append(f.unfollowed, user)
+
+	return nil
+}
+
+func (f *fakeClient) PendingRequests(_ context.Context) ([]models.User, error) {
+	return f.pending, nil
+}
+
+func (f *fakeClient) ApproveRequest(_ context.Context, user models.User) error {
+	f.approved = append(f.approved, user)
+
+	return nil
+}
+
+func (f *fakeClient) DeclineRequest(_ context.Context, user models.User) error {
+	f.declined = append(f.declined, user)
+
+	return nil
+}
+
+// GetUserByName looks for the user in followers and followings.
+func (f *fakeClient) GetUserByName(_ context.Context, username string) (models.User, error) {
+	for _, list := range [][]models.User{f.followers, f.followings} {
+		for _, u := range list {
+			if u.UserName == username {
+				return u, nil
+			}
+		}
+	}
+
+	return models.User{}, clientErrors.ErrUserNotFound
+}
+
+func (f *fakeClient) IsUseless(_ context.Context, user models.User, _ int) (bool, error) {
+	return f.useless[user.UserName], nil
+}
+
+// newTestService creates Service with fake client and local storage.
+func newTestService(t testing.TB, cl client.Client) *Service {
+	t.Helper()
+
+	storage, err := db.Connect(context.Background(), db.Params{
+		LocalDB:     true,
+		MongoParams: db.MongoParams{},
+	})
+	require.NoError(t, err)
+
+	const limit = 100
+
+	return &Service{
+		instagram: instagram{
+			client:    cl,
+			whitelist: newWhitelist(nil),
+			limits: limits{
+				unFollow: limit,
+			},
+		},
+		storage:   storage,
+		incognito: false,
+	}
+}
+
+var errInsertUpload = errors.New("insert upload failed")
+
+// failingUploadsDB is a storage which fails to store uploads.
+type failingUploadsDB struct {
+	db.DB
+}
+
+func (failingUploadsDB) InsertUpload(_ context.Context, _ models.Upload) error {
+	return errInsertUpload
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/actions"
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// follow follows user and stores outgoing request when account is private and request waits for approval.
+func (svc *Service) follow(ctx context.Context, u models.User) error {
+	status, err := svc.instagram.Client().Follow(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	if status != models.FollowStatusRequested {
+		return nil
+	}
+
+	log.WithField(ctx, "username", u.UserName).Info("Follow request sent, waits for approval")
+
+	batch := models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{u}, time.Now())
+
+	if err = svc.storeUsers(ctx, batch); err != nil {
+		// follow request is already sent, so it is not a reason to fail the action.
+		log.WithError(ctx, err).WithField("username", u.UserName).Warn("Failed to store outgoing request")
+	}
+
+	return nil
+}
+
+// GetOutgoingRequests returns sent follow requests that still wait for approval, oldest first.
+// Requests of users that are already followed are considered approved.
+func (svc *Service) GetOutgoingRequests(ctx context.Context) ([]models.OutgoingRequest, error) {
+	requested, err := svc.getAllBatches(ctx, models.UsersBatchTypeOutgoingRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	canceled, err := svc.getAllBatches(ctx, models.UsersBatchTypeCanceledRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	followings, err := svc.GetFollowings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get followings: %w", err)
+	}
+
+	return pendingOutgoing(requested, canceled, followings), nil
+}
+
+// CancelOutgoingRequests cancels outgoing follow requests that wait for approval longer than olderThan.
+// Returns number of canceled requests.
+func (svc *Service) CancelOutgoingRequests(ctx context.Context, olderThan time.Duration) (int, error) {
+	requests, err := svc.GetOutgoingRequests(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	bt := models.UsersBatchTypeCanceledRequests
+
+	users := olderRequests(requests, time.Now().Add(-olderThan))
+	if len(users) == 0 {
+		return 0, makeNoUsersError(bt)
+	}
+
+	// instagram cancels pending request on unfollow.
+	done, actErr := svc.actUsersList(ctx, users, actions.UserActionUnfollow, false)
+
+	batch := models.MakeUsersBatch(bt, done, time.Now())
+
+	if err = svc.storeUsers(ctx, batch); err != nil && !errors.Is(err, ErrNoUsers) {
+		return len(done), fmt.Errorf("store users [%s]: %w", bt.String(), err)
+	}
+
+	return len(done), actErr
+}
+
+func (svc *Service) getAllBatches(ctx context.Context, bt models.UsersBatchType) ([]models.UsersBatch, error) {
+	batches, err := svc.storage.GetAllUsersBatchByType(ctx, bt)
+	if err != nil {
+		if errors.Is(err, db.ErrNoData) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get all users batches [%s]: %w", bt.String(), err)
+	}
+
+	return batches, nil
+}
+
+// pendingOutgoing resolves requests that still wait for approval.
+// Request is pending when it was sent after the last cancel and user is not followed yet.
+// When request was sent several times, the first one after the last cancel is used.
+func pendingOutgoing(requested, canceled []models.UsersBatch, followings []models.User) []models.OutgoingRequest {
+	followed := make(map[int64]bool, len(followings))
+
+	for _, u := range followings {
+		followed[u.ID] = true
+	}
+
+	lastCanceled := make(map[int64]time.Time)
+
+	for _, b := range canceled {
+		for _, u := range b.Users {
+			if b.CreatedAt.After(lastCanceled[u.ID]) {
+				lastCanceled[u.ID] = b.CreatedAt
+			}
+		}
+	}
+
+	pending := make(map[int64]models.OutgoingRequest)
+
+	for _, b := range requested {
+		for _, u := range b.Users {
+			if followed[u.ID] || !b.CreatedAt.After(lastCanceled[u.ID]) {
+				continue
+			}
+
+			if r, ok := pending[u.ID]; ok && !b.CreatedAt.Before(r.RequestedAt) {
+				continue
+			}
+
+			pending[u.ID] = models.OutgoingRequest{
+				User:        u,
+				RequestedAt: b.CreatedAt,
+			}
+		}
+	}
+
+	result := make([]models.OutgoingRequest, 0, len(pending))
+
+	for _, r := range pending {
+		result = append(result, r)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RequestedAt.Equal(result[j].RequestedAt) {
+			return result[i].User.UserName < result[j].User.UserName
+		}
+
+		return result[i].RequestedAt.Before(result[j].RequestedAt)
+	})
+
+	return result
+}
+
+// olderRequests returns users whose requests were sent before the passed time.
+func olderRequests(requests []models.OutgoingRequest, before time.Time) []models.User {
+	users := make([]models.User, 0, len(requests))
+
+	for _, r := range requests {
+		if r.RequestedAt.Before(before) {
+			users = append(users, r.User)
+		}
+	}
+
+	return users
+}
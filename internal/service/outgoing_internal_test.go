@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/actions"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func Test_pendingOutgoing(t *testing.T) {
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+
+	day1 := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	tests := []struct {
+		name       string
+		requested  []models.UsersBatch
+		canceled   []models.UsersBatch
+		followings []models.User
+		want       []models.OutgoingRequest
+	}{
+		{
+			name: "oldest first",
+			requested: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{bob}, day2),
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{alice}, day1),
+			},
+			want: []models.OutgoingRequest{
+				{User: alice, RequestedAt: day1},
+				{User: bob, RequestedAt: day2},
+			},
+		},
+		{
+			name: "first request is kept",
+			requested: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{alice}, day1),
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{alice}, day2),
+			},
+			want: []models.OutgoingRequest{
+				{User: alice, RequestedAt: day1},
+			},
+		},
+		{
+			name: "approved and canceled are skipped",
+			requested: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{alice, bob, carol}, day1),
+			},
+			canceled: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeCanceledRequests, []models.User{bob}, day2),
+			},
+			followings: []models.User{carol},
+			want: []models.OutgoingRequest{
+				{User: alice, RequestedAt: day1},
+			},
+		},
+		{
+			name: "requested again after cancel",
+			requested: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{bob}, day1),
+				models.MakeUsersBatch(models.UsersBatchTypeOutgoingRequests, []models.User{bob}, day3),
+			},
+			canceled: []models.UsersBatch{
+				models.MakeUsersBatch(models.UsersBatchTypeCanceledRequests, []models.User{bob}, day2),
+			},
+			want: []models.OutgoingRequest{
+				{User: bob, RequestedAt: day3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := pendingOutgoing(tt.requested, tt.canceled, tt.followings)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_CancelOutgoingRequests(t *testing.T) {
+	ctx := context.Background()
+
+	dave := models.MakeUser(60, "dave", "Dave")
+	erin := models.MakeUser(61, "erin", "Erin")
+
+	cl := &fakeClient{
+		private: map[string]bool{erin.UserName: true},
+	}
+
+	svc := newTestService(t, cl)
+
+	done, err := svc.actUsersList(ctx, []models.User{dave, erin}, actions.UserActionFollow, false)
+	require.NoError(t, err)
+	assert.Len(t, done, 2)
+
+	got, err := svc.GetOutgoingRequests(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, erin, got[0].User)
+
+	// request is too fresh to be canceled.
+	_, err = svc.CancelOutgoingRequests(ctx, time.Hour)
+	require.ErrorIs(t, err, ErrNoUsers)
+
+	count, err := svc.CancelOutgoingRequests(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []models.User{erin}, cl.unfollowed)
+
+	got, err = svc.GetOutgoingRequests(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"slices"
+	"sync"
+
+	"github.com/obalunenko/instadiff-cli/internal/config"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+)
+
+// Overlay defaults used when values are not set in config.
+const (
+	defaultWatermarkScale = 0.2
+	defaultOverlayOpacity = 1
+	defaultTextSize       = 48
+)
+
+// photoTypes are media types overlays are drawn on by default.
+var photoTypes = []media.Type{media.TypeFeedPhoto, media.TypeCarousel, media.TypeStoryPhoto}
+
+// accountOverlays are overlays configured for the logged-in account.
+type accountOverlays struct {
+	types    []media.Type
+	overlays media.Overlays
+}
+
+// forType returns overlays of the media type, zero value when they are not configured for it.
+func (a accountOverlays) forType(mt media.Type) media.Overlays {
+	if !slices.Contains(a.types, mt) {
+		return media.Overlays{}
+	}
+
+	return a.overlays
+}
+
+// lazyOverlays loads account overlays on the first use, so watermark is read only by commands that process photos.
+type lazyOverlays struct {
+	once     sync.Once
+	cfg      config.Config
+	username string
+	overlays accountOverlays
+	err      error
+}
+
+func newLazyOverlays(cfg config.Config, username string) *lazyOverlays {
+	return &lazyOverlays{
+		once:     sync.Once{},
+		cfg:      cfg,
+		username: username,
+		overlays: accountOverlays{},
+		err:      nil,
+	}
+}
+
+// forType returns overlays of the media type, zero value when they are not configured.
+func (l *lazyOverlays) forType(mt media.Type) (media.Overlays, error) {
+	if l == nil {
+		return media.Overlays{}, nil
+	}
+
+	l.once.Do(func() {
+		l.overlays, l.err = makeOverlays(l.cfg, l.username)
+	})
+
+	if l.err != nil {
+		return media.Overlays{}, l.err
+	}
+
+	return l.overlays.forType(mt), nil
+}
+
+// AccountOverlays returns overlays configured for the account media type, zero value when there are none.
+func AccountOverlays(cfg config.Config, username string, mt media.Type) (media.Overlays, error) {
+	a, err := makeOverlays(cfg, username)
+	if err != nil {
+		return media.Overlays{}, err
+	}
+
+	return a.forType(mt), nil
+}
+
+// makeOverlays loads account overlays from config, watermark image is read from disk.
+func makeOverlays(cfg config.Config, username string) (accountOverlays, error) {
+	o, ok := cfg.MediaOverlay(username)
+	if !ok {
+		return accountOverlays{}, nil
+	}
+
+	a := accountOverlays{
+		types: photoTypes,
+	}
+
+	if len(o.Types) != 0 {
+		a.types = make([]media.Type, 0, len(o.Types))
+
+		for _, t := range o.Types {
+			mt, err := media.Parse(t)
+			if err != nil {
+				return accountOverlays{}, fmt.Errorf("overlay media type: %w", err)
+			}
+
+			a.types = append(a.types, mt)
+		}
+	}
+
+	if o.Watermark != nil {
+		w, err := makeWatermark(*o.Watermark)
+		if err != nil {
+			return accountOverlays{}, err
+		}
+
+		a.overlays.Watermark = &w
+	}
+
+	for i, t := range o.Texts {
+		text, err := makeText(t)
+		if err != nil {
+			return accountOverlays{}, fmt.Errorf("overlay text %d: %w", i+1, err)
+		}
+
+		a.overlays.Texts = append(a.overlays.Texts, text)
+	}
+
+	if err := a.overlays.Validate(); err != nil {
+		return accountOverlays{}, fmt.Errorf("overlays of [%s]: %w", username, err)
+	}
+
+	return a, nil
+}
+
+func makeWatermark(cfg config.WatermarkOverlay) (media.Watermark, error) {
+	img, err := media.LoadWatermark(cfg.Path)
+	if err != nil {
+		return media.Watermark{}, err
+	}
+
+	w := media.Watermark{
+		Image:    img,
+		Position: media.PositionBottomRight,
+		Scale:    cmp.Or(cfg.Scale, defaultWatermarkScale),
+		Opacity:  cmp.Or(cfg.Opacity, defaultOverlayOpacity),
+		Margin:   cfg.Margin,
+	}
+
+	if cfg.Position != "" {
+		if w.Position, err = media.ParsePosition(cfg.Position); err != nil {
+			return media.Watermark{}, fmt.Errorf("watermark: %w", err)
+		}
+	}
+
+	return w, nil
+}
+
+func makeText(cfg config.TextOverlay) (media.Text, error) {
+	t := media.Text{
+		Text:     cfg.Text,
+		Position: media.PositionBottomLeft,
+		Size:     cmp.Or(cfg.Size, defaultTextSize),
+		Color:    color.White,
+		Opacity:  cmp.Or(cfg.Opacity, defaultOverlayOpacity),
+		Margin:   cfg.Margin,
+	}
+
+	var err error
+
+	if cfg.Position != "" {
+		if t.Position, err = media.ParsePosition(cfg.Position); err != nil {
+			return media.Text{}, err
+		}
+	}
+
+	if cfg.Color != "" {
+		if t.Color, err = media.ParseColor(cfg.Color); err != nil {
+			return media.Text{}, err
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/config"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+)
+
+func loadOverlaysConfig(t *testing.T, overlays string) config.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), buf.Bytes(), 0o600))
+
+	path := filepath.Join(dir, "config.json")
+	content := fmt.Sprintf(overlays, filepath.Join(dir, "logo.png"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"media": {"overlays": `+content+`}}`), 0o600))
+
+	cfg, err := config.Load(context.Background(), path)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+func TestAccountOverlays(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlays string
+		username string
+		mt       media.Type
+		want     func(t *testing.T, o media.Overlays)
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "defaults",
+			overlays: `{"user1": {"watermark": {"path": %q}, "texts": [{"text": "@user1"}]}}`,
+			username: "user1",
+			mt:       media.TypeStoryPhoto,
+			want: func(t *testing.T, o media.Overlays) {
+				require.NotNil(t, o.Watermark)
+				assert.Equal(t, media.PositionBottomRight, o.Watermark.Position)
+				assert.InDelta(t, defaultWatermarkScale, o.Watermark.Scale, 0)
+				assert.InDelta(t, defaultOverlayOpacity, o.Watermark.Opacity, 0)
+
+				require.Len(t, o.Texts, 1)
+				assert.Equal(t, media.PositionBottomLeft, o.Texts[0].Position)
+				assert.InDelta(t, defaultTextSize, o.Texts[0].Size, 0)
+				assert.Equal(t, color.White, o.Texts[0].Color)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "configured",
+			overlays: `{"user1": {"types": ["carousel"], "watermark": {"path": %q, "position": "top_left", "scale": 0.5},
+				"texts": [{"text": "hi", "position": "center", "color": "#FF0000", "size": 20, "opacity": 0.5}]}}`,
+			username: "user1",
+			mt:       media.TypeCarousel,
+			want: func(t *testing.T, o media.Overlays) {
+				require.NotNil(t, o.Watermark)
+				assert.Equal(t, media.PositionTopLeft, o.Watermark.Position)
+				assert.InDelta(t, 0.5, o.Watermark.Scale, 0)
+
+				require.Len(t, o.Texts, 1)
+				assert.Equal(t, media.PositionCenter, o.Texts[0].Position)
+				assert.InDelta(t, 0.5, o.Texts[0].Opacity, 0)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "other media type",
+			overlays: `{"user1": {"types": ["carousel"], "watermark": {"path": %q}}}`,
+			username: "user1",
+			mt:       media.TypeFeedPhoto,
+			want: func(t *testing.T, o media.Overlays) {
+				assert.True(t, o.IsZero())
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "other account",
+			overlays: `{"user1": {"watermark": {"path": %q}}}`,
+			username: "user2",
+			mt:       media.TypeFeedPhoto,
+			want: func(t *testing.T, o media.Overlays) {
+				assert.True(t, o.IsZero())
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:     "unknown position",
+			overlays: `{"user1": {"watermark": {"path": %q, "position": "middle"}}}`,
+			username: "user1",
+			mt:       media.TypeFeedPhoto,
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "invalid opacity",
+			overlays: `{"user1": {"watermark": {"path": %q}, "texts": [{"text": "hi", "opacity": 3}]}}`,
+			username: "user1",
+			mt:       media.TypeFeedPhoto,
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, media.ErrInvalidOverlay)
+			},
+		},
+		{
+			name:     "missing watermark",
+			overlays: `{"user1": {"watermark": {"path": "%s.missing"}}}`,
+			username: "user1",
+			mt:       media.TypeFeedPhoto,
+			wantErr:  assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadOverlaysConfig(t, tt.overlays)
+
+			got, err := AccountOverlays(cfg, tt.username, tt.mt)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			tt.want(t, got)
+		})
+	}
+}
+
+func TestLazyOverlays(t *testing.T) {
+	t.Run("relative watermark path", func(t *testing.T) {
+		cfg := loadOverlaysConfig(t, `{"user1": {"watermark": {"path": %q}}}`)
+
+		o, ok := cfg.MediaOverlay("user1")
+		require.True(t, ok)
+
+		// config is rewritten with the watermark path relative to its directory, not to the working one.
+		path := filepath.Join(filepath.Dir(o.Watermark.Path), "config.json")
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"media": {"overlays": {"user1": {"watermark": {"path": "logo.png"}}}}}`), 0o600))
+
+		cfg, err := config.Load(context.Background(), path)
+		require.NoError(t, err)
+
+		got, err := newLazyOverlays(cfg, "user1").forType(media.TypeFeedPhoto)
+		require.NoError(t, err)
+		assert.NotNil(t, got.Watermark)
+	})
+
+	t.Run("missing watermark fails on use", func(t *testing.T) {
+		cfg := loadOverlaysConfig(t, `{"user1": {"watermark": {"path": "%s.missing"}}}`)
+
+		l := newLazyOverlays(cfg, "user1")
+
+		_, err := l.forType(media.TypeFeedPhoto)
+		require.Error(t, err)
+
+		_, err = l.forType(media.TypeCarousel)
+		require.Error(t, err)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		var l *lazyOverlays
+
+		got, err := l.forType(media.TypeFeedPhoto)
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+	})
+}
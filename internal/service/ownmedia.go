@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// OwnMedia returns the last feed posts and active stories of the account.
+func (svc *Service) OwnMedia(ctx context.Context, posts int) (models.OwnMedia, error) {
+	p, err := svc.instagram.Client().Posts(ctx, posts)
+	if err != nil {
+		return models.OwnMedia{}, fmt.Errorf("get posts: %w", err)
+	}
+
+	s, err := svc.instagram.Client().Stories(ctx)
+	if err != nil {
+		return models.OwnMedia{}, fmt.Errorf("get stories: %w", err)
+	}
+
+	return models.OwnMedia{
+		Posts:   p,
+		Stories: s,
+	}, nil
+}
+
+// DeleteMedia deletes feed post or story by id. Instagram needs media type to delete it, so media is looked up
+// among active stories and the last posts feed posts.
+func (svc *Service) DeleteMedia(ctx context.Context, id string, posts int) error {
+	if err := checkMediaID(id); err != nil {
+		return err
+	}
+
+	own, err := svc.OwnMedia(ctx, posts)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range own.Stories {
+		if s.ID == id {
+			return svc.instagram.Client().DeleteStory(ctx, s)
+		}
+	}
+
+	for _, p := range own.Posts {
+		if p.ID == id {
+			return svc.instagram.Client().DeletePost(ctx, p)
+		}
+	}
+
+	return fmt.Errorf("[%s]: %w", id, ErrMediaNotFound)
+}
+
+// ArchiveMedia hides feed post from the profile by id, it could be restored from the archive in the app.
+func (svc *Service) ArchiveMedia(ctx context.Context, id string) error {
+	if err := checkMediaID(id); err != nil {
+		return err
+	}
+
+	return svc.instagram.Client().ArchiveMedia(ctx, id)
+}
+
+// EditCaption replaces caption of the feed post by id with the caption and hashtags of the post options.
+// Caption is validated the same way as on upload.
+func (svc *Service) EditCaption(ctx context.Context, id string, post media.PostOptions) error {
+	if err := checkMediaID(id); err != nil {
+		return err
+	}
+
+	if err := post.Validate(media.TypeFeedPhoto, 1); err != nil {
+		return fmt.Errorf("caption: %w", err)
+	}
+
+	return svc.instagram.Client().EditCaption(ctx, id, post.FullCaption())
+}
+
+func checkMediaID(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return ErrEmptyMediaID
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_OwnMedia(t *testing.T) {
+	posts := []models.Post{
+		{ID: "2_1", Code: "C2", TakenAt: time.Unix(200, 0), Likes: 3, Caption: "second"},
+		{ID: "1_1", Code: "C1", TakenAt: time.Unix(100, 0), Comments: 1},
+	}
+	stories := []models.Story{{ID: "3_1", TakenAt: time.Unix(300, 0), Views: 5}}
+
+	svc := newTestService(t, &fakeClient{posts: posts, stories: stories})
+
+	got, err := svc.OwnMedia(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.OwnMedia{
+		Posts:   posts[:1],
+		Stories: stories,
+	}, got)
+}
+
+func TestService_ManageMedia(t *testing.T) {
+	ctx := context.Background()
+
+	cl := &fakeClient{
+		posts:   []models.Post{{ID: "1_1", MediaType: "PHOTO"}, {ID: "5_1", MediaType: "CAROUSEL"}},
+		stories: []models.Story{{ID: "6_1", MediaType: "VIDEO"}},
+	}
+	svc := newTestService(t, cl)
+
+	require.ErrorIs(t, svc.DeleteMedia(ctx, " ", 10), ErrEmptyMediaID)
+	require.ErrorIs(t, svc.ArchiveMedia(ctx, ""), ErrEmptyMediaID)
+	require.ErrorIs(t, svc.EditCaption(ctx, "", media.PostOptions{}), ErrEmptyMediaID)
+
+	require.NoError(t, svc.DeleteMedia(ctx, "1_1", 10))
+	require.NoError(t, svc.DeleteMedia(ctx, "6_1", 10))
+	// post is out of the last posts window.
+	require.ErrorIs(t, svc.DeleteMedia(ctx, "5_1", 1), ErrMediaNotFound)
+	require.NoError(t, svc.ArchiveMedia(ctx, "2_1"))
+	require.NoError(t, svc.EditCaption(ctx, "3_1", media.PostOptions{Caption: "Sunset", Hashtags: []string{"sea"}}))
+
+	err := svc.EditCaption(ctx, "4_1", media.PostOptions{Caption: strings.Repeat("a", 2201)})
+	require.ErrorIs(t, err, media.ErrCaptionTooLong)
+
+	err = svc.EditCaption(ctx, "4_1", media.PostOptions{Hashtags: []string{"bad tag"}})
+	require.ErrorIs(t, err, media.ErrInvalidHashtag)
+
+	assert.Equal(t, []string{"1_1", "6_1"}, cl.deleted)
+	assert.Equal(t, []string{"2_1"}, cl.archived)
+	assert.Equal(t, map[string]string{"3_1": "Sunset\n\n#sea"}, cl.captions)
+}
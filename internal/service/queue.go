@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// maxQueueRetryDelay caps backoff between scheduled post upload attempts.
+const maxQueueRetryDelay = 24 * time.Hour
+
+// QueueRetry describes how failed scheduled posts are retried.
+type QueueRetry struct {
+	// MaxAttempts is a total number of upload attempts, post is marked failed after the last one.
+	MaxAttempts int
+	// Delay is a backoff after the first failed attempt, it doubles after every next one.
+	Delay time.Duration
+}
+
+// delay returns backoff after attempt-th failed attempt.
+func (r QueueRetry) delay(attempt int) time.Duration {
+	d := r.Delay
+
+	for i := 1; i < attempt && d < maxQueueRetryDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, maxQueueRetryDelay)
+}
+
+// checkQueueStorage fails when storage does not keep scheduled posts between runs.
+func (svc *Service) checkQueueStorage() error {
+	if svc.localStorage {
+		return ErrQueueLocalStorage
+	}
+
+	return nil
+}
+
+// AddToQueue schedules post upload. Files are checked to exist and stored by absolute path,
+// they are read at upload time. Media type, files number and post options are validated.
+func (svc *Service) AddToQueue(
+	ctx context.Context,
+	files []string,
+	mt media.Type,
+	post media.PostOptions,
+	at time.Time,
+) (models.QueueItem, error) {
+	if err := svc.checkQueueStorage(); err != nil {
+		return models.QueueItem{}, err
+	}
+
+	if !mt.Valid() {
+		return models.QueueItem{}, fmt.Errorf("media type is invalid: %s", mt)
+	}
+
+	if err := mt.CheckFilesNum(len(files)); err != nil {
+		return models.QueueItem{}, err
+	}
+
+	if err := post.Validate(mt, len(files)); err != nil {
+		return models.QueueItem{}, fmt.Errorf("post options: %w", err)
+	}
+
+	paths := make([]string, 0, len(files))
+
+	for _, f := range files {
+		p, err := filepath.Abs(f)
+		if err != nil {
+			return models.QueueItem{}, fmt.Errorf("file path [%s]: %w", f, err)
+		}
+
+		if _, err = os.Stat(p); err != nil {
+			return models.QueueItem{}, fmt.Errorf("file [%s]: %w", f, err)
+		}
+
+		paths = append(paths, p)
+	}
+
+	id, err := newQueueID()
+	if err != nil {
+		return models.QueueItem{}, err
+	}
+
+	item := models.QueueItem{
+		ID:          id,
+		Files:       paths,
+		Type:        mt,
+		Post:        post,
+		ScheduledAt: at,
+		Status:      models.QueueStatusPending,
+		CreatedAt:   time.Now(),
+	}
+
+	if err = svc.storage.InsertQueueItem(ctx, item); err != nil {
+		return models.QueueItem{}, fmt.Errorf("store queue item: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetQueue returns all scheduled posts, the earliest scheduled first.
+func (svc *Service) GetQueue(ctx context.Context) ([]models.QueueItem, error) {
+	if err := svc.checkQueueStorage(); err != nil {
+		return nil, err
+	}
+
+	items, err := svc.storage.GetAllQueueItems(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoData) {
+		return nil, fmt.Errorf("get queue items: %w", err)
+	}
+
+	return items, nil
+}
+
+// RemoveFromQueue removes scheduled post by id.
+func (svc *Service) RemoveFromQueue(ctx context.Context, id string) error {
+	if err := svc.checkQueueStorage(); err != nil {
+		return err
+	}
+
+	err := svc.storage.DeleteQueueItem(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoData) {
+			return fmt.Errorf("[%s]: %w", id, ErrQueueItemNotFound)
+		}
+
+		return fmt.Errorf("delete queue item: %w", err)
+	}
+
+	return nil
+}
+
+// RunQueue uploads due scheduled posts once and stores results. Failed post is retried with backoff
+// until retry attempts are exhausted. Photos are processed with the pipeline of their media type,
+// default one is used when it is not set. Returns number of uploaded posts.
+func (svc *Service) RunQueue(
+	ctx context.Context,
+	pipelines map[media.Type]media.Pipeline,
+	retry QueueRetry,
+) (int, error) {
+	items, err := svc.GetQueue(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var uploaded int
+
+	for _, item := range items {
+		if !item.Due(time.Now()) {
+			continue
+		}
+
+		pipeline, ok := pipelines[item.Type]
+		if !ok {
+			pipeline = media.DefaultPipeline(item.Type)
+		}
+
+		id, err := svc.UploadFiles(ctx, item.Files, item.Type, item.Post, pipeline)
+		if errors.Is(err, ErrUploadHistory) {
+			// post is published already, retry would publish it again.
+			log.WithError(ctx, err).WithFields(log.Fields{
+				"id":       item.ID,
+				"media_id": id,
+			}).Warn("Scheduled post is uploaded, but not stored in upload history")
+
+			err = nil
+		}
+
+		if err != nil && ctx.Err() != nil {
+			return uploaded, ctx.Err()
+		}
+
+		now := time.Now()
+
+		if err != nil {
+			item.Attempts++
+			item.LastError = err.Error()
+			item.NextAttemptAt = now.Add(retry.delay(item.Attempts))
+
+			if item.Attempts >= retry.MaxAttempts {
+				item.Status = models.QueueStatusFailed
+			}
+
+			log.WithError(ctx, err).WithFields(log.Fields{
+				"id":       item.ID,
+				"attempts": item.Attempts,
+				"status":   item.Status.String(),
+			}).Warn("Failed to upload scheduled post")
+		} else {
+			item.Status = models.QueueStatusDone
+			item.UploadedAt = now
+			uploaded++
+		}
+
+		if err = svc.storage.UpdateQueueItem(ctx, item); err != nil {
+			return uploaded, fmt.Errorf("store queue item [%s]: %w", item.ID, err)
+		}
+	}
+
+	return uploaded, nil
+}
+
+func newQueueID() (string, error) {
+	const size = 4
+
+	b := make([]byte, size)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/config"
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_AddToQueue(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	photo := writePhoto(t, dir, "a.jpg", 10)
+	at := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		files   []string
+		mt      media.Type
+		post    media.PostOptions
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "feed photo",
+			files:   []string{photo},
+			mt:      media.TypeFeedPhoto,
+			post:    media.PostOptions{Caption: "hello"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid type",
+			files:   []string{photo},
+			mt:      media.TypeUndefined,
+			wantErr: assert.Error,
+		},
+		{
+			name:  "files number",
+			files: []string{photo},
+			mt:    media.TypeCarousel,
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, media.ErrFilesNum)
+			},
+		},
+		{
+			name:  "story post options",
+			files: []string{photo},
+			mt:    media.TypeStoryPhoto,
+			post:  media.PostOptions{Caption: "hello"},
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, media.ErrStoryPostOptions)
+			},
+		},
+		{
+			name:    "missing file",
+			files:   []string{filepath.Join(dir, "missing.jpg")},
+			mt:      media.TypeFeedPhoto,
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t, &fakeClient{})
+
+			got, err := svc.AddToQueue(ctx, tt.files, tt.mt, tt.post, at)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			assert.NotEmpty(t, got.ID)
+			assert.Equal(t, models.QueueStatusPending, got.Status)
+			assert.Equal(t, tt.post, got.Post)
+
+			items, err := svc.GetQueue(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []models.QueueItem{got}, items)
+		})
+	}
+}
+
+func TestService_RemoveFromQueue(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, &fakeClient{})
+
+	item, err := svc.AddToQueue(ctx, []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{}, time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RemoveFromQueue(ctx, item.ID))
+	require.ErrorIs(t, svc.RemoveFromQueue(ctx, item.ID), ErrQueueItemNotFound)
+
+	items, err := svc.GetQueue(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestService_Queue_LocalStorage(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, &fakeClient{})
+
+	svc.localStorage = true
+
+	_, err := svc.AddToQueue(ctx, []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{}, time.Now())
+	require.ErrorIs(t, err, ErrQueueLocalStorage)
+
+	_, err = svc.GetQueue(ctx)
+	require.ErrorIs(t, err, ErrQueueLocalStorage)
+
+	require.ErrorIs(t, svc.RemoveFromQueue(ctx, "id"), ErrQueueLocalStorage)
+
+	_, err = svc.RunQueue(ctx, nil, QueueRetry{MaxAttempts: 1, Delay: time.Minute})
+	require.ErrorIs(t, err, ErrQueueLocalStorage)
+}
+
+func TestService_RunQueue(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+
+	now := time.Now()
+
+	due, err := svc.AddToQueue(ctx, []string{writePhoto(t, dir, "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{Caption: "due"}, now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = svc.AddToQueue(ctx, []string{writePhoto(t, dir, "b.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{Caption: "later"}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	retry := QueueRetry{MaxAttempts: 2, Delay: time.Hour}
+
+	// The first attempt fails and is postponed.
+	cl.uploadErr = errors.New("upload failed")
+
+	n, err := svc.RunQueue(ctx, nil, retry)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	items, err := svc.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	got := items[0]
+	assert.Equal(t, due.ID, got.ID)
+	assert.Equal(t, models.QueueStatusPending, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "upload failed", got.LastError)
+	assert.True(t, got.NextAttemptAt.After(now.Add(59*time.Minute)))
+	assert.False(t, got.Due(time.Now()))
+
+	// The retry succeeds when backoff is passed.
+	cl.uploadErr = nil
+	got.NextAttemptAt = now
+	require.NoError(t, svc.storage.UpdateQueueItem(ctx, got))
+
+	n, err = svc.RunQueue(ctx, nil, retry)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"due"}, cl.uploaded)
+
+	items, err = svc.GetQueue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.QueueStatusDone, items[0].Status)
+	assert.False(t, items[0].UploadedAt.IsZero())
+	assert.Equal(t, models.QueueStatusPending, items[1].Status)
+}
+
+func TestService_RunQueue_Failed(t *testing.T) {
+	ctx := context.Background()
+
+	cl := &fakeClient{uploadErr: errors.New("upload failed")}
+	svc := newTestService(t, cl)
+
+	_, err := svc.AddToQueue(ctx, []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{}, time.Now())
+	require.NoError(t, err)
+
+	_, err = svc.RunQueue(ctx, nil, QueueRetry{MaxAttempts: 1, Delay: time.Minute})
+	require.NoError(t, err)
+
+	items, err := svc.GetQueue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.QueueStatusFailed, items[0].Status)
+	assert.False(t, items[0].Due(time.Now().Add(time.Hour)))
+}
+
+func TestService_RunQueue_HistoryFailed(t *testing.T) {
+	ctx := context.Background()
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+	svc.storage = failingUploadsDB{DB: svc.storage}
+
+	_, err := svc.AddToQueue(ctx, []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{Caption: "due"}, time.Now())
+	require.NoError(t, err)
+
+	retry := QueueRetry{MaxAttempts: 2, Delay: time.Minute}
+
+	n, err := svc.RunQueue(ctx, nil, retry)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	items, err := svc.GetQueue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.QueueStatusDone, items[0].Status)
+	assert.Equal(t, 0, items[0].Attempts)
+
+	// Uploaded post is not published again.
+	n, err = svc.RunQueue(ctx, nil, retry)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, []string{"due"}, cl.uploaded)
+}
+
+func TestService_Queue_SharedByUsername(t *testing.T) {
+	ctx := context.Background()
+
+	// storages are keyed by collection name as mongo collections are.
+	storages := make(map[string]db.DB)
+
+	connect := connectDB
+
+	t.Cleanup(func() {
+		connectDB = connect
+	})
+
+	connectDB = func(ctx context.Context, params db.Params) (db.DB, error) {
+		if _, ok := storages[params.MongoParams.Collection]; !ok {
+			dbc, err := db.Connect(ctx, db.Params{LocalDB: true})
+			if err != nil {
+				return nil, err
+			}
+
+			storages[params.MongoParams.Collection] = dbc
+		}
+
+		return storages[params.MongoParams.Collection], nil
+	}
+
+	online, err := newService(ctx, config.Config{}, Params{}, &fakeClient{username: "User1"})
+	require.NoError(t, err)
+
+	offline, err := newOfflineService(ctx, config.Config{}, "user1")
+	require.NoError(t, err)
+
+	item, err := offline.AddToQueue(ctx, []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}, media.TypeFeedPhoto,
+		media.PostOptions{}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	items, err := online.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, item.ID, items[0].ID)
+	assert.Len(t, storages, 1)
+}
+
+func TestQueueRetry_delay(t *testing.T) {
+	r := QueueRetry{MaxAttempts: 10, Delay: time.Minute}
+
+	assert.Equal(t, time.Minute, r.delay(1))
+	assert.Equal(t, 4*time.Minute, r.delay(3))
+	assert.Equal(t, maxQueueRetryDelay, r.delay(20))
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/actions"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+// GetPendingRequests returns users that requested to follow the account.
+func (svc *Service) GetPendingRequests(ctx context.Context) ([]models.User, error) {
+	stop := spinner.Set("Fetching pending follow requests", "", "yellow")
+	defer stop()
+
+	users, err := svc.instagram.client.PendingRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get pending requests: %w", err)
+	}
+
+	return users, nil
+}
+
+// ApproveRequests approves pending follow requests of passed users.
+func (svc *Service) ApproveRequests(ctx context.Context, usernames []string) (int, error) {
+	return svc.processRequestsByUsernames(ctx, usernames, actions.UserActionApprove)
+}
+
+// DeclineRequests declines pending follow requests of passed users.
+func (svc *Service) DeclineRequests(ctx context.Context, usernames []string) (int, error) {
+	return svc.processRequestsByUsernames(ctx, usernames, actions.UserActionDecline)
+}
+
+// ApproveAllRequests approves all pending follow requests. When declineUseless is set,
+// requests from useless accounts (bots, business accounts or mass-followers) are declined.
+// Returns number of processed (approved and declined) requests, also when processing was interrupted by error.
+func (svc *Service) ApproveAllRequests(ctx context.Context, declineUseless bool) (int, error) {
+	pending, err := svc.GetPendingRequests(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pending) == 0 {
+		return 0, makeNoUsersError(models.UsersBatchTypeApprovedRequests)
+	}
+
+	approve := pending
+
+	var declined int
+
+	if declineUseless {
+		var decline []models.User
+
+		approve, decline = svc.splitUseless(ctx, pending)
+
+		declined, err = svc.processRequests(ctx, decline, actions.UserActionDecline)
+
+		log.WithField(ctx, "count", declined).Info("Declined useless requests")
+
+		if err != nil && !errors.Is(err, ErrNoUsers) {
+			return declined, err
+		}
+	}
+
+	approved, err := svc.processRequests(ctx, approve, actions.UserActionApprove)
+
+	log.WithField(ctx, "count", approved).Info("Approved requests")
+
+	if errors.Is(err, ErrNoUsers) && declined != 0 {
+		err = nil
+	}
+
+	return declined + approved, err
+}
+
+// splitUseless splits users to useful and useless. Users that failed to check are skipped.
+func (svc *Service) splitUseless(ctx context.Context, users []models.User) ([]models.User, []models.User) {
+	stop := spinner.Set("Detecting useless accounts", "", "yellow")
+	defer stop()
+
+	useful := make([]models.User, 0, len(users))
+	useless := make([]models.User, 0, len(users))
+
+	for _, u := range users {
+		isUseless, err := svc.isUseless(ctx, u)
+		if err != nil {
+			log.WithError(ctx, err).WithField("username", u.UserName).Warn("Failed to check user, skipped")
+
+			continue
+		}
+
+		if isUseless {
+			useless = append(useless, u)
+
+			continue
+		}
+
+		useful = append(useful, u)
+	}
+
+	return useful, useless
+}
+
+func (svc *Service) processRequestsByUsernames(ctx context.Context, usernames []string, act actions.UserAction) (int, error) {
+	if len(usernames) == 0 {
+		return 0, ErrNoUsernamesPassed
+	}
+
+	pending, err := svc.GetPendingRequests(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	users, notFound := filterByUsernames(pending, usernames)
+	if len(notFound) != 0 {
+		log.WithError(ctx, fmt.Errorf("[ %s ]: %w", strings.Join(notFound, ","), ErrNoPendingRequest)).
+			Warn("Some users have no pending requests")
+	}
+
+	return svc.processRequests(ctx, users, act)
+}
+
+// processRequests approves or declines requests and stores decisions.
+func (svc *Service) processRequests(ctx context.Context, users []models.User, act actions.UserAction) (int, error) {
+	var bt models.UsersBatchType
+
+	switch act {
+	case actions.UserActionApprove:
+		bt = models.UsersBatchTypeApprovedRequests
+	case actions.UserActionDecline:
+		bt = models.UsersBatchTypeDeclinedRequests
+	default:
+		return 0, fmt.Errorf("not supported action for requests: %s", act.String())
+	}
+
+	if len(users) == 0 {
+		return 0, makeNoUsersError(bt)
+	}
+
+	done, actErr := svc.actUsersList(ctx, users, act, false)
+
+	batch := models.MakeUsersBatch(bt, done, time.Now())
+
+	if err := svc.storeUsers(ctx, batch); err != nil && !errors.Is(err, ErrNoUsers) {
+		return len(done), fmt.Errorf("store users [%s]: %w", bt.String(), err)
+	}
+
+	return len(done), actErr
+}
+
+// filterByUsernames returns users with passed usernames and usernames that were not found.
+func filterByUsernames(users []models.User, usernames []string) ([]models.User, []string) {
+	byName := make(map[string]models.User, len(users))
+
+	for _, u := range users {
+		byName[strings.ToLower(u.UserName)] = u
+	}
+
+	found := make([]models.User, 0, len(usernames))
+
+	var notFound []string
+
+	for _, un := range usernames {
+		u, ok := byName[strings.ToLower(un)]
+		if !ok {
+			notFound = append(notFound, un)
+
+			continue
+		}
+
+		found = append(found, u)
+	}
+
+	return found, notFound
+}
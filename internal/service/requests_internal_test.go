@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func Test_filterByUsernames(t *testing.T) {
+	users := []models.User{
+		models.MakeUser(1, "alice", "Alice"),
+		models.MakeUser(2, "Bob", "Bob"),
+	}
+
+	found, notFound := filterByUsernames(users, []string{"bob", "carol", "alice"})
+
+	assert.Equal(t, []models.User{
+		models.MakeUser(2, "Bob", "Bob"),
+		models.MakeUser(1, "alice", "Alice"),
+	}, found)
+	assert.Equal(t, []string{"carol"}, notFound)
+}
+
+func TestService_ApproveAllRequests(t *testing.T) {
+	grace := models.MakeUser(50, "grace", "Grace")
+	bot := models.MakeUser(51, "bot_1234", "")
+
+	tests := []struct {
+		name           string
+		declineUseless bool
+		wantApproved   []models.User
+		wantDeclined   []models.User
+	}{
+		{
+			name:           "approve all",
+			declineUseless: false,
+			wantApproved:   []models.User{grace, bot},
+			wantDeclined:   nil,
+		},
+		{
+			name:           "decline useless",
+			declineUseless: true,
+			wantApproved:   []models.User{grace},
+			wantDeclined:   []models.User{bot},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cl := &fakeClient{
+				pending: []models.User{grace, bot},
+				useless: map[string]bool{bot.UserName: true},
+			}
+
+			svc := newTestService(t, cl)
+
+			got, err := svc.ApproveAllRequests(ctx, tt.declineUseless)
+			require.NoError(t, err)
+
+			assert.Equal(t, len(tt.wantApproved)+len(tt.wantDeclined), got)
+			assert.Equal(t, tt.wantApproved, cl.approved)
+			assert.Equal(t, tt.wantDeclined, cl.declined)
+
+			approved, err := svc.storage.GetLastUsersBatchByType(ctx, models.UsersBatchTypeApprovedRequests)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantApproved, approved.Users)
+
+			if tt.wantDeclined != nil {
+				declined, err := svc.storage.GetLastUsersBatchByType(ctx, models.UsersBatchTypeDeclinedRequests)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantDeclined, declined.Users)
+			}
+		})
+	}
+}
+
+func TestService_DeclineRequests(t *testing.T) {
+	ctx := context.Background()
+
+	grace := models.MakeUser(50, "grace", "Grace")
+
+	cl := &fakeClient{
+		pending: []models.User{grace},
+	}
+
+	svc := newTestService(t, cl)
+
+	got, err := svc.DeclineRequests(ctx, []string{"grace", "unknown"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, got)
+	assert.Equal(t, []models.User{grace}, cl.declined)
+
+	_, err = svc.DeclineRequests(ctx, nil)
+	require.ErrorIs(t, err, ErrNoUsernamesPassed)
+}
+
+func TestService_ApproveAllRequests_NotLimited(t *testing.T) {
+	ctx := context.Background()
+
+	pending := []models.User{
+		models.MakeUser(60, "ivan", "Ivan"),
+		models.MakeUser(61, "judy", "Judy"),
+		models.MakeUser(62, "bot_4321", ""),
+	}
+
+	cl := &fakeClient{
+		pending: pending,
+		useless: map[string]bool{"bot_4321": true},
+	}
+
+	svc := newTestService(t, cl)
+	// unfollow limit doesn't apply to follow requests.
+	svc.instagram.limits.unFollow = 1
+
+	got, err := svc.ApproveAllRequests(ctx, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, got)
+	assert.Equal(t, pending[:2], cl.approved)
+	assert.Equal(t, pending[2:], cl.declined)
+}
+
+func TestService_ApproveAllRequests_OnlyUseless(t *testing.T) {
+	ctx := context.Background()
+
+	bot := models.MakeUser(70, "bot_1111", "")
+
+	cl := &fakeClient{
+		pending: []models.User{bot},
+		useless: map[string]bool{bot.UserName: true},
+	}
+
+	svc := newTestService(t, cl)
+
+	got, err := svc.ApproveAllRequests(ctx, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, got)
+	assert.Equal(t, []models.User{bot}, cl.declined)
+}
@@ -6,7 +6,7 @@
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +17,9 @@
 	"github.com/obalunenko/instadiff-cli/internal/actions"
 	"github.com/obalunenko/instadiff-cli/internal/client"
 	clientErrors "github.com/obalunenko/instadiff-cli/internal/client/errors"
+	"github.com/obalunenko/instadiff-cli/internal/client/ratelimit"
+	"github.com/obalunenko/instadiff-cli/internal/client/retry"
+	"github.com/obalunenko/instadiff-cli/internal/client/transport"
 	"github.com/obalunenko/instadiff-cli/internal/config"
 	"github.com/obalunenko/instadiff-cli/internal/db"
 	"github.com/obalunenko/instadiff-cli/internal/media"
@@ -30,16 +33,18 @@ type Service struct {
 	instagram instagram
 	storage   db.DB
 	incognito bool
+	overlays  *lazyOverlays
+	// localStorage is set when storage is in memory and data is lost on exit.
+	localStorage bool
 }
 
 type instagram struct {
 	client    client.Client
-	whitelist map[string]struct{}
+	whitelist *whitelist
 	limits    limits
-	sleep     time.Duration
 }
 
-func (i instagram) Whitelist() map[string]struct{} {
+func (i instagram) Whitelist() *whitelist {
 	return i.whitelist
 }
 
@@ -51,10 +56,6 @@ func (i instagram) Client() client.Client {
 	return i.client
 }
 
-func (i instagram) Sleep() time.Duration {
-	return i.sleep
-}
-
 type limits struct {
 	unFollow int
 }
@@ -67,6 +68,7 @@ type Params struct {
 	SessionPath string
 	IsIncognito bool
 	Username    string
+	ForceLogin  bool
 }
 
 // New creates new instance of Service instance and returns closure func that will stop service.
@@ -78,15 +80,21 @@ type Params struct {
 // }
 // defer svc.Stop().
 func New(ctx context.Context, cfg config.Config, params Params) (*Service, error) {
-	cl, err := client.New(ctx, client.Params{
-		SessionPath: params.SessionPath,
-		Sleep:       cfg.Sleep(),
-		Username:    params.Username,
-	})
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := client.New(ctx, cp)
 	if err != nil {
 		return nil, fmt.Errorf("make client: %w", err)
 	}
 
+	return newService(ctx, cfg, params, cl)
+}
+
+// newService creates Service for logged-in client.
+func newService(ctx context.Context, cfg config.Config, params Params, cl client.Client) (*Service, error) {
 	uname := cl.Username(ctx)
 
 	log.WithField(ctx, "username", uname).Info("Logged-in")
@@ -94,34 +102,159 @@ func New(ctx context.Context, cfg config.Config, params Params) (*Service, error
 	stop := spinner.Set("Connecting to DB", "", "yellow")
 	defer stop()
 
-	dbc, err := db.Connect(ctx, db.Params{
-		LocalDB: cfg.IsLocalDBEnabled(),
-		MongoParams: db.MongoParams{
-			URL:        cfg.MongoConfigURL(),
-			Database:   cfg.MongoDBName(),
-			Collection: db.BuildCollectionName(uname),
-		},
-	})
+	dbc, err := connectStorage(ctx, cfg, uname)
 	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
+		return nil, err
 	}
 
 	svc := Service{
 		instagram: instagram{
 			client:    cl,
-			whitelist: cfg.Whitelist(),
+			whitelist: newWhitelist(cfg.Whitelist()),
 			limits: limits{
 				unFollow: cfg.UnFollowLimits(),
 			},
-			sleep: cfg.Sleep(),
 		},
-		storage:   dbc,
-		incognito: params.IsIncognito,
+		storage:      dbc,
+		incognito:    params.IsIncognito,
+		overlays:     newLazyOverlays(cfg, uname),
+		localStorage: cfg.IsLocalDBEnabled(),
+	}
+
+	if err = svc.loadWhitelisted(ctx); err != nil {
+		return nil, err
 	}
 
 	return &svc, nil
 }
 
+// NewOffline creates Service for commands that work with stored data only, instagram login is not performed.
+// Account is taken from params, username is asked when it is not set.
+func NewOffline(ctx context.Context, cfg config.Config, params Params) (*Service, error) {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// username is taken from the stored session to use the same storage as logged-in service does.
+	uname, err := client.SessionUsername(cp)
+	if err != nil {
+		return nil, fmt.Errorf("session username: %w", err)
+	}
+
+	return newOfflineService(ctx, cfg, uname)
+}
+
+// newOfflineService creates Service without client for the account username.
+func newOfflineService(ctx context.Context, cfg config.Config, uname string) (*Service, error) {
+	dbc, err := connectStorage(ctx, cfg, uname)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		instagram: instagram{
+			client:    nil,
+			whitelist: newWhitelist(cfg.Whitelist()),
+			limits: limits{
+				unFollow: cfg.UnFollowLimits(),
+			},
+		},
+		storage:      dbc,
+		incognito:    false,
+		overlays:     nil,
+		localStorage: cfg.IsLocalDBEnabled(),
+	}, nil
+}
+
+// connectDB is replaced in tests to not depend on mongo.
+var connectDB = db.Connect
+
+// connectStorage connects to the account storage, it is shared by services of the same username.
+func connectStorage(ctx context.Context, cfg config.Config, uname string) (db.DB, error) {
+	dbc, err := connectDB(ctx, db.Params{
+		LocalDB: cfg.IsLocalDBEnabled(),
+		MongoParams: db.MongoParams{
+			URL:        cfg.MongoConfigURL(),
+			Database:   cfg.MongoDBName(),
+			Collection: db.BuildCollectionName(uname),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db connect: %w", err)
+	}
+
+	return dbc, nil
+}
+
+func makeClientParams(cfg config.Config, params Params) (client.Params, error) {
+	hp, err := makeHTTP(cfg)
+	if err != nil {
+		return client.Params{}, fmt.Errorf("http config: %w", err)
+	}
+
+	return client.Params{
+		SessionPath:    params.SessionPath,
+		Username:       params.Username,
+		EncryptSession: cfg.EncryptSession(),
+		ForceLogin:     params.ForceLogin,
+		Retry:          makeRetryPolicy(cfg),
+		Pacing:         makePacing(cfg),
+		HTTP:           hp,
+	}, nil
+}
+
+func makeHTTP(cfg config.Config) (transport.Params, error) {
+	tlsVersion, err := transport.ParseTLSVersion(cfg.HTTPTLSMinVersion())
+	if err != nil {
+		return transport.Params{}, err
+	}
+
+	return transport.Params{
+		Proxy:                 cfg.HTTPProxy(),
+		AccountProxies:        cfg.HTTPAccountProxies(),
+		Timeout:               cfg.HTTPTimeout(),
+		DialTimeout:           cfg.HTTPDialTimeout(),
+		TLSHandshakeTimeout:   cfg.HTTPTLSHandshakeTimeout(),
+		ResponseHeaderTimeout: cfg.HTTPResponseHeaderTimeout(),
+		MinTLSVersion:         tlsVersion,
+		InsecureSkipVerify:    cfg.HTTPTLSInsecureSkipVerify(),
+		RoundTripper:          nil,
+		RecordPath:            os.Getenv(transport.EnvRecord),
+	}, nil
+}
+
+func makeRetryPolicy(cfg config.Config) retry.Policy {
+	return retry.Policy{
+		MaxAttempts:    cfg.RetryAttempts(),
+		BaseDelay:      cfg.RetryDelay(),
+		RateLimitDelay: cfg.RetryRateLimitDelay(),
+		MaxDelay:       cfg.RetryMaxDelay(),
+	}
+}
+
+func makePacing(cfg config.Config) ratelimit.Params {
+	write := ratelimit.Rule{
+		PerMinute: cfg.PacingWritePerMinute(),
+		Burst:     cfg.PacingWriteBurst(),
+		Jitter:    cfg.PacingWriteJitter(),
+	}
+
+	// legacy sleep between actions is used when writes pacing is not configured.
+	if write.PerMinute <= 0 && cfg.Sleep() > 0 {
+		write.PerMinute = float64(time.Minute) / float64(cfg.Sleep())
+	}
+
+	return ratelimit.Params{
+		Read: ratelimit.Rule{
+			PerMinute: cfg.PacingReadPerMinute(),
+			Burst:     cfg.PacingReadBurst(),
+			Jitter:    cfg.PacingReadJitter(),
+		},
+		Write: write,
+	}
+}
+
 // Stop stops the service and closes clients connections.
 func (svc *Service) Stop(ctx context.Context) error {
 	var errs error
@@ -172,6 +305,8 @@ func (svc *Service) getUsers(ctx context.Context, bt models.UsersBatchType) ([]m
 		users, err = svc.instagram.client.Followers(ctx)
 	case models.UsersBatchTypeFollowings:
 		users, err = svc.instagram.client.Followings(ctx)
+	case models.UsersBatchTypeBlocked:
+		users, err = svc.instagram.client.BlockedUsers(ctx)
 	default:
 		return nil, fmt.Errorf("not supported batch type for this func: %s", bt.String())
 	}
@@ -207,6 +342,8 @@ func (svc *Service) findDiffUsers(ctx context.Context, users []models.User, bt m
 		lbt, nbt = models.UsersBatchTypeLostFollowers, models.UsersBatchTypeNewFollowers
 	case models.UsersBatchTypeFollowings:
 		lbt, nbt = models.UsersBatchTypeLostFollowings, models.UsersBatchTypeNewFollowings
+	case models.UsersBatchTypeBlocked:
+		lbt, nbt = models.UsersBatchTypeUnblocked, models.UsersBatchTypeNewBlocked
 	default:
 		return fmt.Errorf("not supported batch type for this func: %s", bt.String())
 	}
@@ -279,19 +416,7 @@ func (svc *Service) GetNotMutualFollowers(ctx context.Context) ([]models.User, e
 	stop := spinner.Set("Detecting not mutual followers", "", "yellow")
 	defer stop()
 
-	followersMap := make(map[int64]struct{}, len(followers))
-
-	for _, fu := range followers {
-		followersMap[fu.ID] = struct{}{}
-	}
-
-	var notmutual = make([]models.User, 0, len(followings))
-
-	for _, fu := range followings {
-		if _, mutual := followersMap[fu.ID]; !mutual {
-			notmutual = append(notmutual, fu)
-		}
-	}
+	notmutual := NotMutual(followers, followings)
 
 	bt := models.UsersBatchTypeNotMutual
 
@@ -308,6 +433,25 @@ func (svc *Service) GetNotMutualFollowers(ctx context.Context) ([]models.User, e
 	return notmutual, nil
 }
 
+// NotMutual returns followings that don't follow back.
+func NotMutual(followers, followings []models.User) []models.User {
+	followersMap := make(map[int64]struct{}, len(followers))
+
+	for _, fu := range followers {
+		followersMap[fu.ID] = struct{}{}
+	}
+
+	var notmutual = make([]models.User, 0, len(followings))
+
+	for _, fu := range followings {
+		if _, mutual := followersMap[fu.ID]; !mutual {
+			notmutual = append(notmutual, fu)
+		}
+	}
+
+	return notmutual
+}
+
 // UnFollow removes user from followings.
 func (svc *Service) UnFollow(ctx context.Context, user models.User) error {
 	log.WithField(ctx, "username", user.UserName).Debug("Unfollow user")
@@ -342,7 +486,7 @@ func (svc *Service) UnFollowAllNotMutualExceptWhitelisted(ctx context.Context) (
 
 	log.WithFields(ctx, log.Fields{
 		"count":       len(notMutual),
-		"whitelisted": len(svc.instagram.Whitelist()),
+		"whitelisted": svc.instagram.Whitelist().len(),
 	}).Info("Not mutual followers")
 
 	diff := svc.whitelistNotMutual(notMutual)
@@ -375,12 +519,10 @@ func (svc *Service) FollowUsers(ctx context.Context, usernames []string) (int, e
 func (svc *Service) whitelistNotMutual(notMutual []models.User) []models.User {
 	result := make([]models.User, 0, len(notMutual))
 
-	whitelist := svc.instagram.Whitelist()
-
 	for i := range notMutual {
 		u := notMutual[i]
 
-		if _, exist := whitelist[u.UserName]; !exist {
+		if !svc.IsWhitelisted(u) {
 			result = append(result, u)
 		}
 	}
@@ -388,6 +530,20 @@ func (svc *Service) whitelistNotMutual(notMutual []models.User) []models.User {
 	return result
 }
 
+// UsersByUsernames returns users by the name passed. Not found users are skipped.
+func (svc *Service) UsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	users, err := svc.getUsersByUsername(ctx, usernames)
+	if err != nil {
+		if !errors.Is(err, ErrUserNotFound) {
+			return nil, fmt.Errorf("get users by usernames: %w", err)
+		}
+
+		log.WithError(ctx, err).Warn("Some users from list failed to fetch")
+	}
+
+	return users, nil
+}
+
 func (svc *Service) getUsersByUsername(ctx context.Context, usernames []string) ([]models.User, error) {
 	stop := spinner.Set("Fetching users by names", "", "yellow")
 	defer stop()
@@ -436,13 +592,9 @@ func (svc *Service) RemoveFollowersByUsername(ctx context.Context, usernames []s
 type userListProcessFunc func(ctx context.Context, uslist []models.User) (int, error)
 
 func (svc *Service) processByUsernames(ctx context.Context, usernames []string, f userListProcessFunc) (int, error) {
-	uslist, err := svc.getUsersByUsername(ctx, usernames)
+	uslist, err := svc.UsersByUsernames(ctx, usernames)
 	if err != nil {
-		if !errors.Is(err, ErrUserNotFound) {
-			return 0, fmt.Errorf("get users by usernames: %w", err)
-		}
-
-		log.WithError(ctx, err).Warn("Some users from list failed to fetch")
+		return 0, err
 	}
 
 	return f(ctx, uslist)
@@ -469,6 +621,18 @@ func (svc *Service) followUsers(ctx context.Context, users []models.User) (int,
 }
 
 func (svc *Service) actUsers(ctx context.Context, users []models.User, act actions.UserAction, useWhitelist bool) (int, error) {
+	done, err := svc.actUsersList(ctx, users, act, useWhitelist)
+
+	return len(done), err
+}
+
+// actUsersList makes action over users and returns users that were processed successfully.
+func (svc *Service) actUsersList(
+	ctx context.Context,
+	users []models.User,
+	act actions.UserAction,
+	useWhitelist bool,
+) ([]models.User, error) {
 	const (
 		double    = 2
 		errsLimit = 3
@@ -478,18 +642,12 @@ func (svc *Service) actUsers(ctx context.Context, users []models.User, act actio
 	defer pBar.Finish()
 
 	var (
-		skipped bool
-		count   int
+		done    = make([]models.User, 0, len(users))
 		errsNum int
 	)
 
-	for i, u := range users {
-		if i != 0 && !skipped {
-			time.Sleep(svc.instagram.Sleep())
-		}
-
-		skipped = false
-
+	// requests are paced by the client, so there is no need to sleep between actions.
+	for _, u := range users {
 		if ctx.Err() != nil {
 			break
 		}
@@ -502,8 +660,6 @@ func (svc *Service) actUsers(ctx context.Context, users []models.User, act actio
 
 		if err != nil {
 			if errors.Is(err, ErrUserInWhitelist) {
-				skipped = true
-
 				continue
 			}
 
@@ -512,23 +668,40 @@ func (svc *Service) actUsers(ctx context.Context, users []models.User, act actio
 				WithField("action", act.String()).
 				Error("Failed to make action")
 
+			// client already retried transient errors, so here only decide whether it makes sense to continue.
+			switch {
+			case clientErrors.IsFatal(err):
+				return done, fmt.Errorf("%w: %w", ErrCorrupted, err)
+			case errors.Is(err, clientErrors.ErrRateLimited):
+				return done, fmt.Errorf("%w: %w", ErrRateLimited, err)
+			case errors.Is(err, clientErrors.ErrNotFound), errors.Is(err, clientErrors.ErrPrivate):
+				// user specific errors, could be just skipped.
+				continue
+			}
+
 			errsNum++
 
 			if errsNum >= errsLimit {
-				return count, ErrCorrupted
+				return done, ErrCorrupted
 			}
 
 			continue
 		}
 
-		count++
+		done = append(done, u)
 
-		if count >= svc.instagram.limits.unFollow {
-			return count, ErrLimitExceed
+		if isLimited(act) && len(done) >= svc.instagram.limits.unFollow {
+			return done, ErrLimitExceed
 		}
 	}
 
-	return count, nil
+	return done, nil
+}
+
+// isLimited reports whether action is restricted by the configured limit. Answers to incoming
+// follow requests are not limited, they are initiated by other users.
+func isLimited(act actions.UserAction) bool {
+	return act != actions.UserActionApprove && act != actions.UserActionDecline
 }
 
 func (svc *Service) actUser(ctx context.Context, u models.User, act actions.UserAction, useWhitelist bool) error {
@@ -540,20 +713,12 @@ func (svc *Service) actUser(ctx context.Context, u models.User, act actions.User
 		WithField("user_id", u.ID).
 		Debug("Action finished")
 
-	whitelist := svc.instagram.Whitelist()
-
 	canUseWhitelist := act == actions.UserActionUnfollow ||
 		act == actions.UserActionBlock ||
 		act == actions.UserActionRemove
 
 	if canUseWhitelist && useWhitelist {
-		if _, exist := whitelist[u.UserName]; exist {
-			return ErrUserInWhitelist
-		}
-
-		const base = 10
-
-		if _, exist := whitelist[strconv.FormatInt(u.ID, base)]; exist {
+		if svc.IsWhitelisted(u) {
 			return ErrUserInWhitelist
 		}
 	}
@@ -564,13 +729,17 @@ func (svc *Service) actUser(ctx context.Context, u models.User, act actions.User
 
 	switch act {
 	case actions.UserActionFollow:
-		err = cli.Follow(ctx, u)
+		err = svc.follow(ctx, u)
 	case actions.UserActionUnfollow:
 		err = cli.Unfollow(ctx, u)
 	case actions.UserActionBlock:
 		err = cli.Block(ctx, u)
 	case actions.UserActionUnblock:
 		err = cli.Unblock(ctx, u)
+	case actions.UserActionApprove:
+		err = cli.ApproveRequest(ctx, u)
+	case actions.UserActionDecline:
+		err = cli.DeclineRequest(ctx, u)
 	case actions.UserActionRemove:
 		if err = cli.Block(ctx, u); err != nil {
 			return fmt.Errorf("block user: %w", err)
@@ -733,6 +902,8 @@ func (svc *Service) getDiff(ctx context.Context, dt models.DiffType) ([]models.U
 		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewFollowers, models.UsersBatchTypeLostFollowers}
 	case models.DiffTypeFollowings:
 		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewFollowings, models.UsersBatchTypeLostFollowings}
+	case models.DiffTypeBlocked:
+		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewBlocked, models.UsersBatchTypeUnblocked}
 	default:
 		return nil, fmt.Errorf("unsupported diff type [%s]", dt.String())
 	}
@@ -799,6 +970,8 @@ func (svc *Service) getHistoryDiff(ctx context.Context, dt models.DiffType) (mod
 		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewFollowers, models.UsersBatchTypeLostFollowers}
 	case models.DiffTypeFollowings:
 		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewFollowings, models.UsersBatchTypeLostFollowings}
+	case models.DiffTypeBlocked:
+		batchTypes = []models.UsersBatchType{models.UsersBatchTypeNewBlocked, models.UsersBatchTypeUnblocked}
 	default:
 		return models.DiffHistory{}, fmt.Errorf("unsupported diff type [%s]", dt.String())
 	}
@@ -871,23 +1044,83 @@ func getNew(oldlist, newlist []models.User) []models.User {
 	return diff
 }
 
-// UploadMedia uploads media to profile.
-func (svc *Service) UploadMedia(ctx context.Context, file io.Reader, mt media.Type) error {
+// UploadMedia uploads media to profile. Photos are processed by the pipeline, videos are uploaded as is.
+// Post options and pipeline are validated before files processing. Returns id of the created media.
+func (svc *Service) UploadMedia(
+	ctx context.Context,
+	files []io.Reader,
+	mt media.Type,
+	post media.PostOptions,
+	pipeline media.Pipeline,
+) (string, error) {
 	stop := spinner.Set("Uploading media", "", "yellow")
 	defer stop()
 
-	if file == nil {
-		return errors.New("file is empty")
+	if err := post.Validate(mt, len(files)); err != nil {
+		return "", fmt.Errorf("post options: %w", err)
 	}
 
-	if !mt.Valid() {
-		return fmt.Errorf("media type is invalid: %s", mt)
+	// account overlays are used unless the pipeline has its own ones.
+	if pipeline.Overlays.IsZero() {
+		o, err := svc.overlays.forType(mt)
+		if err != nil {
+			return "", fmt.Errorf("media overlays: %w", err)
+		}
+
+		pipeline.Overlays = o
 	}
 
-	file, err := media.AddBorders(file, mt)
+	prepared, err := prepareMedia(files, mt, pipeline)
 	if err != nil {
-		return fmt.Errorf("add borders: %w", err)
+		return "", err
+	}
+
+	return svc.instagram.Client().UploadMedia(ctx, prepared, mt, post)
+}
+
+// RenderMedia processes photos by the pipeline the same way UploadMedia does, without upload and login.
+func RenderMedia(files []io.Reader, mt media.Type, pipeline media.Pipeline) ([]io.Reader, error) {
+	if mt.IsVideo() {
+		return nil, fmt.Errorf("%s: %w", mt.String(), ErrRenderVideo)
+	}
+
+	return prepareMedia(files, mt, pipeline)
+}
+
+// prepareMedia validates files number and pipeline for the media type and processes photos, videos are returned as is.
+func prepareMedia(files []io.Reader, mt media.Type, pipeline media.Pipeline) ([]io.Reader, error) {
+	if !mt.Valid() {
+		return nil, fmt.Errorf("media type is invalid: %s", mt)
+	}
+
+	if err := mt.CheckFilesNum(len(files)); err != nil {
+		return nil, err
+	}
+
+	if !mt.IsVideo() {
+		if err := pipeline.Validate(mt); err != nil {
+			return nil, fmt.Errorf("pipeline: %w", err)
+		}
+	}
+
+	prepared := make([]io.Reader, 0, len(files))
+
+	for i, file := range files {
+		if file == nil {
+			return nil, fmt.Errorf("file %d is empty", i+1)
+		}
+
+		if !mt.IsVideo() {
+			var err error
+
+			file, err = pipeline.Process(file)
+			if err != nil {
+				return nil, fmt.Errorf("process file %d: %w", i+1, err)
+			}
+		}
+
+		prepared = append(prepared, file)
 	}
 
-	return svc.instagram.Client().UploadMedia(ctx, file, mt)
+	return prepared, nil
 }
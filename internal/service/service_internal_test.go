@@ -83,3 +83,41 @@ type args struct {
 		})
 	}
 }
+
+func TestNotMutual(t *testing.T) {
+	type args struct {
+		followers  []models.User
+		followings []models.User
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []models.User
+	}{
+		{
+			name: "not following back",
+			args: args{
+				followers:  []models.User{{ID: 1}, {ID: 2}},
+				followings: []models.User{{ID: 1}, {ID: 3}, {ID: 4}},
+			},
+			want: []models.User{{ID: 3}, {ID: 4}},
+		},
+		{
+			name: "all mutual",
+			args: args{
+				followers:  []models.User{{ID: 1}, {ID: 2}},
+				followings: []models.User{{ID: 2}},
+			},
+			want: []models.User{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NotMutual(tt.args.followers, tt.args.followings))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/client"
+	"github.com/obalunenko/instadiff-cli/internal/config"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// Session management functions work with the stored session only, so they don't need Service instance.
+
+// RotateSessionKey re-encrypts stored session with a new key.
+func RotateSessionKey(ctx context.Context, cfg config.Config, params Params) error {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return err
+	}
+
+	return client.RotateSessionKey(ctx, cp)
+}
+
+// SessionStatus returns stored session details and checks if it is still valid.
+func SessionStatus(ctx context.Context, cfg config.Config, params Params) (models.SessionInfo, error) {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return models.SessionInfo{}, err
+	}
+
+	return client.SessionStatus(ctx, cp)
+}
+
+// Login refreshes stored session or logs in when session is not valid.
+// With params.ForceLogin stored session is replaced with a new one. Returns logged-in username.
+func Login(ctx context.Context, cfg config.Config, params Params) (string, error) {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return "", err
+	}
+
+	cl, err := client.New(ctx, cp)
+	if err != nil {
+		return "", fmt.Errorf("make client: %w", err)
+	}
+
+	uname := cl.Username(ctx)
+
+	log.WithField(ctx, "username", uname).Info("Logged-in")
+
+	return uname, nil
+}
+
+// Logout logs out stored session and removes session file.
+func Logout(ctx context.Context, cfg config.Config, params Params) error {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return err
+	}
+
+	return client.LogoutSession(ctx, cp)
+}
+
+// ExportSession exports stored session to the file, so it could be imported on another machine.
+func ExportSession(ctx context.Context, cfg config.Config, params Params, dst string) error {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return err
+	}
+
+	return client.ExportSession(ctx, cp, dst)
+}
+
+// ImportSession imports session from the file. Returns session username.
+func ImportSession(ctx context.Context, cfg config.Config, params Params, src string) (string, error) {
+	cp, err := makeClientParams(cfg, params)
+	if err != nil {
+		return "", err
+	}
+
+	return client.ImportSession(ctx, cp, src)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+	"github.com/obalunenko/instadiff-cli/pkg/spinner"
+)
+
+// TrackStoryViewers fetches viewers of active stories and stores them, so they are kept after stories expire.
+// Safe to run repeatedly, every run stores the current viewers. Returns number of tracked stories.
+func (svc *Service) TrackStoryViewers(ctx context.Context) (int, error) {
+	stop := spinner.Set("Fetching story viewers", "", "yellow")
+	defer stop()
+
+	cl := svc.instagram.client
+
+	stories, err := cl.Stories(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get stories: %w", err)
+	}
+
+	for i, s := range stories {
+		viewers, err := cl.StoryViewers(ctx, s)
+		if err != nil {
+			return i, fmt.Errorf("get story viewers [%s]: %w", s.ID, err)
+		}
+
+		sv := models.StoryViewers{
+			Story:     s,
+			Users:     viewers,
+			CreatedAt: time.Now(),
+		}
+
+		if err = svc.storage.InsertStoryViewers(ctx, sv); err != nil {
+			return i, fmt.Errorf("store story viewers [%s]: %w", s.ID, err)
+		}
+	}
+
+	return len(stories), nil
+}
+
+// GetStoryViewsReport ranks followers by number of viewed stories according to stored viewers
+// and lists followers that never viewed any.
+func (svc *Service) GetStoryViewsReport(ctx context.Context) (models.StoryViewsReport, error) {
+	records, err := svc.storage.GetAllStoryViewers(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoData) {
+		return models.StoryViewsReport{}, fmt.Errorf("get story viewers: %w", err)
+	}
+
+	followers, err := svc.GetFollowers(ctx)
+	if err != nil {
+		return models.StoryViewsReport{}, fmt.Errorf("get followers: %w", err)
+	}
+
+	return rankStoryViewers(records, followers), nil
+}
+
+// rankStoryViewers counts viewed stories per follower. Story could be stored several times,
+// so viewers of all its records are merged.
+func rankStoryViewers(records []models.StoryViewers, followers []models.User) models.StoryViewsReport {
+	viewed := make(map[string]map[int64]bool)
+
+	for _, r := range records {
+		if viewed[r.Story.ID] == nil {
+			viewed[r.Story.ID] = make(map[int64]bool)
+		}
+
+		for _, u := range r.Users {
+			viewed[r.Story.ID][u.ID] = true
+		}
+	}
+
+	views := make(map[int64]int)
+
+	for _, viewers := range viewed {
+		for id := range viewers {
+			views[id]++
+		}
+	}
+
+	report := models.StoryViewsReport{
+		Stories: len(viewed),
+	}
+
+	for _, u := range followers {
+		if n := views[u.ID]; n > 0 {
+			report.Viewers = append(report.Viewers, models.UserViews{User: u, Views: n})
+
+			continue
+		}
+
+		report.NeverViewed = append(report.NeverViewed, u)
+	}
+
+	sort.SliceStable(report.Viewers, func(i, j int) bool {
+		return report.Viewers[i].Views > report.Viewers[j].Views
+	})
+
+	return report
+}
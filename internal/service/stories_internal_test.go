@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_StoryViews(t *testing.T) {
+	ctx := context.Background()
+
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+	dave := models.MakeUser(4, "dave", "Dave")
+	// not a follower.
+	eve := models.MakeUser(5, "eve", "Eve")
+
+	cl := &fakeClient{
+		followers: []models.User{alice, bob, carol, dave},
+		stories:   []models.Story{{ID: "s1"}, {ID: "s2"}},
+		storyViewers: map[string][]models.User{
+			"s1": {bob, eve},
+			"s2": {bob},
+		},
+	}
+
+	svc := newTestService(t, cl)
+
+	n, err := svc.TrackStoryViewers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// next run sees more viewers of the same story, s1 expired.
+	cl.stories = []models.Story{{ID: "s2"}}
+	cl.storyViewers["s2"] = []models.User{bob, alice}
+
+	n, err = svc.TrackStoryViewers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	got, err := svc.GetStoryViewsReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StoryViewsReport{
+		Stories: 2,
+		Viewers: []models.UserViews{
+			{User: bob, Views: 2},
+			{User: alice, Views: 1},
+		},
+		NeverViewed: []models.User{carol, dave},
+	}, got)
+}
+
+func TestService_TrackStoryViewers_NoStories(t *testing.T) {
+	svc := newTestService(t, &fakeClient{})
+
+	n, err := svc.TrackStoryViewers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
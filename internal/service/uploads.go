@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/obalunenko/logger"
+
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// UploadDir uploads directory items one by one, waiting pause between uploads.
+// Files which content was uploaded before as the same media type are skipped, successfully uploaded files are stored.
+// Local storage is not kept between runs, so then only duplicates within the run are skipped.
+// Failed file doesn't stop the rest, all failures are returned as one error.
+// Items are processed with the pipeline of their media type, default one is used when it is not set.
+func (svc *Service) UploadDir(
+	ctx context.Context,
+	items []media.DirItem,
+	pipelines map[media.Type]media.Pipeline,
+	pause time.Duration,
+) (models.UploadDirReport, error) {
+	var report models.UploadDirReport
+
+	if svc.localStorage {
+		log.Warn(ctx, "Local storage is not kept between runs, files uploaded by previous runs will be uploaded again")
+	}
+
+	uploaded, err := svc.uploadedKeys(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	var errs error
+
+	for _, it := range items {
+		name := filepath.Base(it.File)
+
+		content, err := os.ReadFile(filepath.Clean(it.File))
+		if err != nil {
+			report.Failed = append(report.Failed, name)
+			errs = multierror.Append(errs, fmt.Errorf("read file [%s]: %w", name, err))
+
+			continue
+		}
+
+		hash := contentHash(content)
+		key := uploadKey(hash, it.Type.String())
+
+		if uploaded[key] {
+			report.Skipped = append(report.Skipped, name)
+
+			continue
+		}
+
+		if len(report.Uploaded)+len(report.Failed) > 0 {
+			if err = sleep(ctx, pause); err != nil {
+				return report, err
+			}
+		}
+
+		pipeline, ok := pipelines[it.Type]
+		if !ok {
+			pipeline = media.DefaultPipeline(it.Type)
+		}
+
+		id, err := svc.UploadMedia(ctx, []io.Reader{bytes.NewReader(content)}, it.Type, it.Post, pipeline)
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+
+			report.Failed = append(report.Failed, name)
+			errs = multierror.Append(errs, fmt.Errorf("upload [%s]: %w", name, err))
+
+			log.WithError(ctx, err).WithField("file", name).Error("Failed to upload file")
+
+			continue
+		}
+
+		if err = svc.storeUpload(ctx, name, hash, it.Type, id, time.Now()); err != nil {
+			// file is published already, so it is reported as uploaded to not be retried.
+			errs = multierror.Append(errs, fmt.Errorf("%w: %w", ErrUploadHistory, err))
+
+			log.WithError(ctx, err).WithField("file", name).Warn("Failed to store uploaded file")
+		}
+
+		uploaded[key] = true
+		report.Uploaded = append(report.Uploaded, name)
+	}
+
+	return report, errs
+}
+
+// UploadFiles uploads files as one media the same way UploadMedia does and adds them to the upload history.
+// It returns id of created media. When media is uploaded, but history is not stored,
+// the id is returned with error wrapping ErrUploadHistory, so upload is not retried.
+func (svc *Service) UploadFiles(
+	ctx context.Context,
+	paths []string,
+	mt media.Type,
+	post media.PostOptions,
+	pipeline media.Pipeline,
+) (string, error) {
+	files := make([]io.Reader, 0, len(paths))
+	hashes := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		content, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return "", fmt.Errorf("read file [%s]: %w", p, err)
+		}
+
+		files = append(files, bytes.NewReader(content))
+		hashes = append(hashes, contentHash(content))
+	}
+
+	id, err := svc.UploadMedia(ctx, files, mt, post, pipeline)
+	if err != nil {
+		return "", err
+	}
+
+	// files of the media share upload time to keep them together in the history.
+	now := time.Now()
+
+	var errs error
+
+	for i, p := range paths {
+		if err = svc.storeUpload(ctx, filepath.Base(p), hashes[i], mt, id, now); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	if errs != nil {
+		return id, fmt.Errorf("%w: %w", ErrUploadHistory, errs)
+	}
+
+	return id, nil
+}
+
+// UploadHistory returns uploaded files with ids of created media, the latest first.
+func (svc *Service) UploadHistory(ctx context.Context) ([]models.Upload, error) {
+	uploads, err := svc.storage.GetAllUploads(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoData) {
+		return nil, fmt.Errorf("get uploads: %w", err)
+	}
+
+	history := slices.Clone(uploads)
+
+	slices.SortStableFunc(history, func(a, b models.Upload) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return history, nil
+}
+
+// storeUpload adds uploaded file to the upload history.
+func (svc *Service) storeUpload(ctx context.Context, name, hash string, mt media.Type, mediaID string, at time.Time) error {
+	err := svc.storage.InsertUpload(ctx, models.Upload{
+		Hash:      hash,
+		File:      name,
+		Type:      mt.String(),
+		MediaID:   mediaID,
+		CreatedAt: at,
+	})
+	if err != nil {
+		return fmt.Errorf("store upload [%s]: %w", name, err)
+	}
+
+	return nil
+}
+
+// uploadedKeys returns keys of stored uploads, see uploadKey.
+func (svc *Service) uploadedKeys(ctx context.Context) (map[string]bool, error) {
+	uploads, err := svc.storage.GetAllUploads(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoData) {
+		return nil, fmt.Errorf("get uploads: %w", err)
+	}
+
+	keys := make(map[string]bool, len(uploads))
+
+	for _, u := range uploads {
+		keys[uploadKey(u.Hash, u.Type)] = true
+	}
+
+	return keys, nil
+}
+
+// uploadKey identifies uploaded content by hash and media type, the same photo could be posted to feed and story.
+func uploadKey(hash, mt string) string {
+	return mt + ":" + hash
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/media"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func writePhoto(t *testing.T, dir, name string, size int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, size, size)), nil))
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	return path
+}
+
+func TestService_UploadDir(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	broken := filepath.Join(dir, "broken.jpg")
+	require.NoError(t, os.WriteFile(broken, []byte("not a photo"), 0o600))
+
+	items := []media.DirItem{
+		{File: writePhoto(t, dir, "a.jpg", 10), Type: media.TypeFeedPhoto, Post: media.PostOptions{Caption: "a"}},
+		{File: writePhoto(t, dir, "a_copy.jpg", 10), Type: media.TypeFeedPhoto, Post: media.PostOptions{Caption: "a copy"}},
+		{File: broken, Type: media.TypeFeedPhoto},
+		{File: writePhoto(t, dir, "b.jpg", 20), Type: media.TypeStoryPhoto},
+	}
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+
+	report, err := svc.UploadDir(ctx, items, nil, 0)
+	require.ErrorIs(t, err, media.ErrUnsupportedFormat)
+
+	assert.Equal(t, models.UploadDirReport{
+		Uploaded: []string{"a.jpg", "b.jpg"},
+		Skipped:  []string{"a_copy.jpg"},
+		Failed:   []string{"broken.jpg"},
+	}, report)
+	assert.Equal(t, []string{"a", ""}, cl.uploaded)
+
+	uploads, err := svc.storage.GetAllUploads(ctx)
+	require.NoError(t, err)
+	require.Len(t, uploads, 2)
+	assert.Equal(t, media.TypeStoryPhoto.String(), uploads[1].Type)
+	assert.Equal(t, "media_2", uploads[1].MediaID)
+
+	// Re-run skips uploaded files.
+	report, err = svc.UploadDir(ctx, items[:2], nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a.jpg", "a_copy.jpg"}, report.Skipped)
+	assert.Empty(t, report.Uploaded)
+	assert.Len(t, cl.uploaded, 2)
+
+	// the same photo posted as a story is not a duplicate of the feed post.
+	report, err = svc.UploadDir(ctx, []media.DirItem{{File: items[0].File, Type: media.TypeStoryPhoto}}, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a.jpg"}, report.Uploaded)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestService_UploadDir_HistoryFailed(t *testing.T) {
+	dir := t.TempDir()
+
+	items := []media.DirItem{
+		{File: writePhoto(t, dir, "a.jpg", 10), Type: media.TypeFeedPhoto},
+		{File: writePhoto(t, dir, "a_copy.jpg", 10), Type: media.TypeFeedPhoto},
+	}
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+	svc.storage = failingUploadsDB{DB: svc.storage}
+
+	report, err := svc.UploadDir(context.Background(), items, nil, 0)
+	require.ErrorIs(t, err, ErrUploadHistory)
+
+	// published file is reported as uploaded and its copy is still skipped.
+	assert.Equal(t, models.UploadDirReport{
+		Uploaded: []string{"a.jpg"},
+		Skipped:  []string{"a_copy.jpg"},
+	}, report)
+	assert.Len(t, cl.uploaded, 1)
+}
+
+func TestService_UploadDir_Pause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	t.Cleanup(cancel)
+
+	dir := t.TempDir()
+
+	items := []media.DirItem{
+		{File: writePhoto(t, dir, "a.jpg", 10), Type: media.TypeFeedPhoto},
+		{File: writePhoto(t, dir, "b.jpg", 20), Type: media.TypeFeedPhoto},
+	}
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+
+	// Context is done while waiting before the second upload.
+	report, err := svc.UploadDir(ctx, items, nil, time.Hour)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"a.jpg"}, report.Uploaded)
+}
+
+func TestService_RenderMedia(t *testing.T) {
+	dir := t.TempDir()
+	photo := writePhoto(t, dir, "a.jpg", 10)
+
+	open := func(t *testing.T) []io.Reader {
+		t.Helper()
+
+		content, err := os.ReadFile(photo)
+		require.NoError(t, err)
+
+		return []io.Reader{bytes.NewReader(content)}
+	}
+
+	tests := []struct {
+		name     string
+		mt       media.Type
+		wantSize image.Point
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "feed photo",
+			mt:       media.TypeFeedPhoto,
+			wantSize: image.Pt(1080, 1080),
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "story photo",
+			mt:       media.TypeStoryPhoto,
+			wantSize: image.Pt(1080, 1920),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "video",
+			mt:   media.TypeVideo,
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrRenderVideo)
+			},
+		},
+		{
+			name: "carousel of one file",
+			mt:   media.TypeCarousel,
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, media.ErrFilesNum)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMedia(open(t), tt.mt, media.DefaultPipeline(tt.mt))
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			require.Len(t, got, 1)
+
+			cfg, err := jpeg.DecodeConfig(got[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSize, image.Pt(cfg.Width, cfg.Height))
+		})
+	}
+}
+
+func TestService_UploadFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+
+	files := []string{writePhoto(t, dir, "a.jpg", 10), writePhoto(t, dir, "b.jpg", 20)}
+	pipeline := media.DefaultPipeline(media.TypeCarousel)
+
+	id, err := svc.UploadFiles(ctx, files, media.TypeCarousel, media.PostOptions{Caption: "album"}, pipeline)
+	require.NoError(t, err)
+	assert.Equal(t, "media_1", id)
+
+	_, err = svc.UploadFiles(ctx, []string{filepath.Join(dir, "missing.jpg")}, media.TypeFeedPhoto, media.PostOptions{}, pipeline)
+	require.Error(t, err)
+
+	story := media.DefaultPipeline(media.TypeStoryPhoto)
+
+	_, err = svc.UploadFiles(ctx, files[:1], media.TypeStoryPhoto, media.PostOptions{}, story)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"album", ""}, cl.uploaded)
+
+	history, err := svc.UploadHistory(ctx)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(history))
+
+	for _, u := range history {
+		got = append(got, u.File+" "+u.Type+" "+u.MediaID)
+	}
+
+	// The latest upload goes first, carousel files share media id.
+	assert.Equal(t, []string{
+		"a.jpg story_photo media_2",
+		"a.jpg carousel media_1",
+		"b.jpg carousel media_1",
+	}, got)
+}
+
+func TestService_UploadFiles_HistoryFailed(t *testing.T) {
+	ctx := context.Background()
+
+	cl := &fakeClient{}
+	svc := newTestService(t, cl)
+	svc.storage = failingUploadsDB{DB: svc.storage}
+
+	files := []string{writePhoto(t, t.TempDir(), "a.jpg", 10)}
+
+	id, err := svc.UploadFiles(ctx, files, media.TypeFeedPhoto, media.PostOptions{Caption: "post"}, media.DefaultPipeline(media.TypeFeedPhoto))
+	require.ErrorIs(t, err, ErrUploadHistory)
+	require.ErrorIs(t, err, errInsertUpload)
+
+	// media is uploaded, so its id is returned with history error.
+	assert.Equal(t, "media_1", id)
+	assert.Equal(t, []string{"post"}, cl.uploaded)
+}
+
+func TestService_UploadHistory_Empty(t *testing.T) {
+	svc := newTestService(t, &fakeClient{})
+
+	got, err := svc.UploadHistory(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/obalunenko/instadiff-cli/internal/db"
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+// whitelist holds usernames and ids of users protected from unfollow, block and remove actions.
+// It could be extended at runtime, so access is guarded.
+type whitelist struct {
+	mu   sync.RWMutex
+	keys map[string]struct{}
+}
+
+func newWhitelist(keys map[string]struct{}) *whitelist {
+	wl := &whitelist{
+		keys: make(map[string]struct{}, len(keys)),
+	}
+
+	for k := range keys {
+		wl.keys[k] = struct{}{}
+	}
+
+	return wl
+}
+
+// has checks whether user is whitelisted by username or id.
+func (w *whitelist) has(u models.User) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if _, exist := w.keys[u.UserName]; exist {
+		return true
+	}
+
+	const base = 10
+
+	_, exist := w.keys[strconv.FormatInt(u.ID, base)]
+
+	return exist
+}
+
+func (w *whitelist) add(users []models.User) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, u := range users {
+		w.keys[u.UserName] = struct{}{}
+	}
+}
+
+func (w *whitelist) len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return len(w.keys)
+}
+
+// IsWhitelisted checks whether user is in the config whitelist or was whitelisted by WhitelistUsers.
+func (svc *Service) IsWhitelisted(u models.User) bool {
+	return svc.instagram.Whitelist().has(u)
+}
+
+// WhitelistUsers adds users by the name passed to the stored whitelist, so they are kept
+// on unfollow of not mutual followers. Returns number of newly whitelisted users.
+func (svc *Service) WhitelistUsers(ctx context.Context, usernames []string) (int, error) {
+	var f userListProcessFunc = func(ctx context.Context, uslist []models.User) (int, error) {
+		if len(uslist) == 0 {
+			return 0, makeNoUsersError(models.UsersBatchTypeWhitelisted)
+		}
+
+		stored, err := svc.getWhitelisted(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		added := getNew(stored, uslist)
+		if len(added) == 0 {
+			return 0, nil
+		}
+
+		users := make([]models.User, 0, len(stored)+len(added))
+		users = append(users, stored...)
+		users = append(users, added...)
+
+		batch := models.MakeUsersBatch(models.UsersBatchTypeWhitelisted, users, time.Now())
+
+		if err = svc.storeUsers(ctx, batch); err != nil {
+			return 0, err
+		}
+
+		svc.instagram.Whitelist().add(added)
+
+		return len(added), nil
+	}
+
+	return svc.processByUsernames(ctx, usernames, f)
+}
+
+// loadWhitelisted adds users stored by WhitelistUsers to the whitelist.
+func (svc *Service) loadWhitelisted(ctx context.Context) error {
+	users, err := svc.getWhitelisted(ctx)
+	if err != nil {
+		return err
+	}
+
+	svc.instagram.Whitelist().add(users)
+
+	return nil
+}
+
+func (svc *Service) getWhitelisted(ctx context.Context) ([]models.User, error) {
+	batch, err := svc.storage.GetLastUsersBatchByType(ctx, models.UsersBatchTypeWhitelisted)
+	if err != nil {
+		if errors.Is(err, db.ErrNoData) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get whitelisted users: %w", err)
+	}
+
+	return batch.Users, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/models"
+)
+
+func TestService_WhitelistUsers(t *testing.T) {
+	ctx := context.Background()
+
+	alice := models.MakeUser(1, "alice", "Alice")
+	bob := models.MakeUser(2, "bob", "Bob")
+	carol := models.MakeUser(3, "carol", "Carol")
+
+	cl := &fakeClient{
+		followers:  []models.User{alice},
+		followings: []models.User{alice, bob, carol},
+	}
+
+	// config whitelist could contain ids.
+	cfgWhitelist := map[string]struct{}{"3": {}}
+
+	svc := newTestService(t, cl)
+	svc.instagram.whitelist = newWhitelist(cfgWhitelist)
+
+	assert.True(t, svc.IsWhitelisted(carol))
+	assert.False(t, svc.IsWhitelisted(bob))
+
+	count, err := svc.WhitelistUsers(ctx, []string{"bob", "nobody"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, svc.IsWhitelisted(bob))
+
+	count, err = svc.WhitelistUsers(ctx, []string{"bob"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = svc.WhitelistUsers(ctx, []string{"nobody"})
+	require.ErrorIs(t, err, ErrNoUsers)
+
+	// whitelist is restored from storage on service start.
+	restarted := newTestService(t, cl)
+	restarted.storage = svc.storage
+	restarted.instagram.whitelist = newWhitelist(cfgWhitelist)
+
+	require.NoError(t, restarted.loadWhitelisted(ctx))
+	assert.True(t, restarted.IsWhitelisted(bob))
+
+	notMutual, err := restarted.GetNotMutualFollowers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, restarted.whitelistNotMutual(notMutual))
+
+	_, err = restarted.UnFollowAllNotMutualExceptWhitelisted(ctx)
+	require.ErrorIs(t, err, ErrNoUsers)
+	assert.Empty(t, cl.unfollowed)
+}
@@ -0,0 +1,16 @@
+package session
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEmptyKey returned when passphrase is empty.
+	ErrEmptyKey = errors.New("session key is empty")
+	// ErrWrongKey returned when session could not be decrypted with passed passphrase.
+	ErrWrongKey = errors.New("wrong session key")
+	// ErrNotEncrypted returned when data is not an encrypted session.
+	ErrNotEncrypted = errors.New("session is not encrypted")
+	// ErrCorrupted returned when encrypted session is malformed.
+	ErrCorrupted = errors.New("session file is corrupted")
+)
@@ -0,0 +1,213 @@
+// Package session provides encryption at rest for the client session files.
+package session
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EnvKey is an environment variable that holds passphrase for session encryption.
+	EnvKey = "INSTADIFF_SESSION_KEY"
+	// EnvNewKey is an environment variable that holds new passphrase during key rotation.
+	EnvNewKey = "INSTADIFF_SESSION_NEW_KEY"
+)
+
+// magic marks encrypted session file. Plaintext sessions are goinsta JSON, so they never start with it.
+var magic = []byte("INSTADIFF-SESS-V1\n")
+
+const (
+	saltLen = 16
+	keyLen  = 32
+
+	// scrypt cost parameters.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	// FilePerm is a file permission for session files, they contain auth tokens.
+	FilePerm os.FileMode = 0o600
+)
+
+// IsEncrypted reports whether data is an encrypted session.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Encrypt encrypts data with key derived from passphrase using AES-GCM.
+func Encrypt(passphrase, data []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyKey
+	}
+
+	salt := make([]byte, saltLen)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(magic)+len(salt)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, magic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+
+	return gcm.Seal(out, nonce, data, magic), nil
+}
+
+// Decrypt decrypts data previously encrypted by Encrypt.
+func Decrypt(passphrase, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, ErrNotEncrypted
+	}
+
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyKey
+	}
+
+	data = data[len(magic):]
+
+	if len(data) < saltLen {
+		return nil, ErrCorrupted
+	}
+
+	salt, data := data[:saltLen], data[saltLen:]
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrCorrupted
+	}
+
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	plain, err := gcm.Open(nil, nonce, data, magic)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+
+	return plain, nil
+}
+
+func newGCM(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// Read reads session file and decrypts it if needed.
+// Plaintext sessions are returned as is, so old session files could still be imported.
+func Read(path string, passphrase []byte) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read session file: %w", err)
+	}
+
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	return Decrypt(passphrase, data)
+}
+
+// Write writes session file. Data is encrypted when passphrase is not empty.
+func Write(path string, passphrase, data []byte) error {
+	if len(passphrase) != 0 {
+		var err error
+
+		data, err = Encrypt(passphrase, data)
+		if err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+	}
+
+	if err := writeFile(path, data); err != nil {
+		return fmt.Errorf("write session file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile replaces file atomically through the temp file in the same directory,
+// so the file always gets FilePerm, even when existing one had wider permissions.
+func writeFile(path string, data []byte) error {
+	// CreateTemp creates file with 0o600 permissions.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	defer func() {
+		// no-op after successful rename.
+		_ = os.Remove(tmp)
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err = f.Chmod(FilePerm); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// RotateKey re-encrypts session file with the new passphrase.
+// Plaintext session files will be encrypted.
+func RotateKey(path string, oldPassphrase, newPassphrase []byte) error {
+	if len(newPassphrase) == 0 {
+		return ErrEmptyKey
+	}
+
+	data, err := Read(path, oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	return Write(path, newPassphrase, data)
+}
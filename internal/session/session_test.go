@@ -0,0 +1,132 @@
+package session_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obalunenko/instadiff-cli/internal/session"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	data := []byte(`{"user":"test"}`)
+
+	type args struct {
+		encryptKey []byte
+		decryptKey []byte
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "same key",
+			args: args{
+				encryptKey: []byte("secret"),
+				decryptKey: []byte("secret"),
+			},
+			want:    data,
+			wantErr: nil,
+		},
+		{
+			name: "wrong key",
+			args: args{
+				encryptKey: []byte("secret"),
+				decryptKey: []byte("wrong"),
+			},
+			want:    nil,
+			wantErr: session.ErrWrongKey,
+		},
+		{
+			name: "empty key",
+			args: args{
+				encryptKey: []byte("secret"),
+				decryptKey: nil,
+			},
+			want:    nil,
+			wantErr: session.ErrEmptyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := session.Encrypt(tt.args.encryptKey, data)
+			require.NoError(t, err)
+
+			assert.True(t, session.IsEncrypted(enc))
+			assert.NotContains(t, string(enc), string(data))
+
+			got, err := session.Decrypt(tt.args.decryptKey, enc)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecrypt_NotEncrypted(t *testing.T) {
+	_, err := session.Decrypt([]byte("secret"), []byte(`{"user":"test"}`))
+	require.ErrorIs(t, err, session.ErrNotEncrypted)
+}
+
+func TestRotateKey(t *testing.T) {
+	data := []byte(`{"user":"test"}`)
+
+	path := filepath.Join(t.TempDir(), "test.sess")
+
+	// plaintext session from previous versions.
+	require.NoError(t, session.Write(path, nil, data))
+
+	got, err := session.Read(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	require.NoError(t, session.RotateKey(path, nil, []byte("first")))
+
+	_, err = session.Read(path, nil)
+	require.ErrorIs(t, err, session.ErrEmptyKey)
+
+	require.NoError(t, session.RotateKey(path, []byte("first"), []byte("second")))
+
+	_, err = session.Read(path, []byte("first"))
+	require.ErrorIs(t, err, session.ErrWrongKey)
+
+	got, err = session.Read(path, []byte("second"))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, session.FilePerm, info.Mode().Perm())
+}
+
+func TestWrite_FixesPermissions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.sess")
+
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	require.NoError(t, os.Chmod(path, 0o644))
+
+	require.NoError(t, session.Write(path, []byte("key"), []byte(`{"user":"test"}`)))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, session.FilePerm, info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp file should not be left")
+}